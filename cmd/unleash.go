@@ -18,8 +18,10 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 
@@ -27,11 +29,13 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/singhnishant94/gremlins/internal/cache"
 	"github.com/singhnishant94/gremlins/internal/coverage"
 	"github.com/singhnishant94/gremlins/internal/diff"
 	"github.com/singhnishant94/gremlins/internal/engine"
 	"github.com/singhnishant94/gremlins/internal/engine/workdir"
 	"github.com/singhnishant94/gremlins/internal/exclusion"
+	"github.com/singhnishant94/gremlins/internal/github"
 	"github.com/singhnishant94/gremlins/internal/log"
 	"github.com/singhnishant94/gremlins/internal/mutator"
 	"github.com/singhnishant94/gremlins/internal/report"
@@ -48,19 +52,41 @@ type unleashCmd struct {
 const (
 	commandName = "unleash"
 
+	commentsFileName = "comments.json"
+
 	paramDiff               = "diff"
 	paramGithubToken        = "github-token"
 	paramGithubRepo         = "github-repo"
+	paramCache              = "cache"
+	paramCacheInvalidate    = "cache-invalidate"
 	paramBuildTags          = "tags"
 	paramCoverPackages      = "coverpkg"
+	paramPerTestCoverage    = "per-test-coverage"
 	paramDryRun             = "dry-run"
 	paramOutputStatuses     = "output-statuses"
 	paramOutput             = "output"
+	paramOutputHTML         = "output-html"
+	paramOutputStryker      = "output-stryker"
+	paramOutputSARIF        = "output-sarif"
+	paramOutputJUnit        = "output-junit"
 	paramIntegrationMode    = "integration"
+	paramOverlay            = "overlay"
+	paramSchemata           = "schemata"
+	paramBinaryTimeouts     = "binary-timeouts"
+	paramEquivalentFilter   = "equivalent-filter"
 	paramExcludeFiles       = "exclude-files"
+	paramExcludePackages    = "exclude-packages"
+	paramExcludeFunctions   = "exclude-functions"
+	paramIncludeGenerated   = "include-generated"
+	paramAridPresets        = "arid-presets"
+	paramAridFunctions      = "arid-functions"
+	paramAridPackages       = "arid-packages"
+	paramAridReceivers      = "arid-receivers"
 	paramTestCPU            = "test-cpu"
 	paramWorkers            = "workers"
 	paramTimeoutCoefficient = "timeout-coefficient"
+	paramTimeoutFloor       = "timeout-floor"
+	paramTimeoutCeiling     = "timeout-ceiling"
 
 	// Thresholds.
 	paramThresholdEfficacy  = "threshold-efficacy"
@@ -166,6 +192,8 @@ func run(ctx context.Context, mod gomodule.GoModule, workDir string) (report.Res
 		return report.Results{}, err
 	}
 
+	resCache := loadCache()
+
 	c := coverage.New(workDir, mod)
 
 	exclude, err := exclusion.New()
@@ -181,7 +209,20 @@ func run(ctx context.Context, mod gomodule.GoModule, workDir string) (report.Res
 	wdDealer := workdir.NewCachedDealer(workDir, mod.Root)
 	defer wdDealer.Clean()
 
-	jDealer := engine.NewExecutorDealer(mod, wdDealer, cProfile.Elapsed)
+	dealerOpts := []engine.ExecutorDealerOption{engine.WithPackagesElapsed(cProfile.Packages)}
+	if configuration.Get[bool](configuration.UnleashPerTestCoverageKey) && !configuration.Get[bool](configuration.UnleashDryRunKey) {
+		testsProfile, err := c.RunPerTest(configuration.Get[int](configuration.UnleashWorkersKey))
+		if err != nil {
+			log.Errorf("impossible to gather per-test coverage, all the tests will run for each mutant: %s\n", err)
+		} else {
+			dealerOpts = append(dealerOpts, engine.WithTestsProfile(testsProfile))
+		}
+	}
+
+	var jDealer engine.ExecutorDealer = engine.NewExecutorDealer(mod, wdDealer, cProfile.Elapsed, dealerOpts...)
+	if configuration.Get[bool](configuration.UnleashSchemataKey) {
+		jDealer = engine.NewSchemataExecutorDealer(mod, wdDealer, cProfile.Elapsed, dealerOpts...)
+	}
 
 	codeData := engine.CodeData{
 		Cov:       cProfile.Profile,
@@ -189,12 +230,134 @@ func run(ctx context.Context, mod gomodule.GoModule, workDir string) (report.Res
 		Exclusion: exclude,
 	}
 
-	mut := engine.New(mod, codeData, jDealer)
+	rules, err := engine.NewRules()
+	if err != nil {
+		return report.Results{}, err
+	}
+	if err := engine.ConfigureArid(); err != nil {
+		return report.Results{}, err
+	}
+
+	engineOpts := []engine.Option{engine.WithRules(rules)}
+	pkgs, err := engine.LoadPackages(mod)
+	if err != nil {
+		log.Errorf("impossible to load type information, mutants will be discovered without it: %s\n", err)
+	} else {
+		engineOpts = append(engineOpts, engine.WithPackages(pkgs))
+	}
+	if resCache != nil {
+		engineOpts = append(engineOpts, engine.WithCache(resCache))
+	}
+
+	mut := engine.New(mod, codeData, jDealer, engineOpts...)
 	results := mut.Run(ctx)
 
+	if resCache != nil {
+		if err := resCache.Save(); err != nil {
+			log.Errorf("impossible to save the cache: %s\n", err)
+		}
+	}
+
+	comments := github.Comments(results.Mutants)
+	writeComments(comments)
+	publishReview(ctx, mod, comments)
+
 	return results, nil
 }
 
+// loadCache loads the results cache, if enabled. It must be called before
+// the coverage run, since it changes the current directory and the cache
+// path can be relative.
+// The cache is not used in dry-run, because the mutants are not executed.
+// A cache that can't be read doesn't fail the run, it is just not used.
+func loadCache() *cache.Cache {
+	path := configuration.Get[string](configuration.UnleashCacheKey)
+	if path == "" || configuration.Get[bool](configuration.UnleashDryRunKey) {
+		return nil
+	}
+	invalidate := configuration.Get[bool](configuration.UnleashCacheInvalidateKey)
+
+	c, err := cache.Load(path, cacheFingerprint(), invalidate)
+	if err != nil {
+		log.Errorf("impossible to load the cache, running without it: %s\n", err)
+
+		return nil
+	}
+
+	return c
+}
+
+// fingerprintKeys are the configuration keys which can change the mutants
+// found or their verdicts, in addition to the enablement of the mutations.
+var fingerprintKeys = []string{
+	configuration.UnleashTagsKey,
+	configuration.UnleashIntegrationMode,
+	configuration.UnleashCoverPkgKey,
+	configuration.UnleashPerTestCoverageKey,
+	configuration.UnleashTestCPUKey,
+	configuration.UnleashTimeoutCoefficientKey,
+	configuration.UnleashTimeoutFloorKey,
+	configuration.UnleashTimeoutCeilingKey,
+	configuration.UnleashEquivalentFilterKey,
+	configuration.UnleashRulesKey,
+	configuration.UnleashAridPresetsKey,
+	configuration.UnleashAridFunctionsKey,
+	configuration.UnleashAridPackagesKey,
+	configuration.UnleashAridReceiversKey,
+}
+
+// cacheFingerprint describes the configuration of the run, so that the
+// cache of a run with a different one is discarded.
+func cacheFingerprint() string {
+	var b strings.Builder
+	for _, k := range fingerprintKeys {
+		_, _ = fmt.Fprintf(&b, "%s=%v;", k, configuration.Get[any](k))
+	}
+	for _, mt := range mutator.Types {
+		_, _ = fmt.Fprintf(&b, "%s=%t;", mt, configuration.Get[bool](configuration.MutantTypeEnabledKey(mt)))
+	}
+
+	return b.String()
+}
+
+// writeComments dumps the review comments of the LIVED mutants in the
+// current directory, so that they can be published by external tools.
+func writeComments(comments []github.Comment) {
+	data, err := json.MarshalIndent(comments, "", "    ")
+	if err != nil {
+		log.Errorf("impossible to marshal comments: %s\n", err)
+
+		return
+	}
+	if err := os.WriteFile(commentsFileName, data, 0600); err != nil {
+		log.Errorf("impossible to write comments: %s\n", err)
+	}
+}
+
+// publishReview posts the LIVED mutants as a review on the GitHub pull
+// request, if both the GitHub token and repository are set.
+// Failing to publish doesn't fail the run, since the results are still
+// reported.
+func publishReview(ctx context.Context, mod gomodule.GoModule, comments []github.Comment) {
+	token := configuration.Get[string](configuration.UnleashGithubToken)
+	repo := configuration.Get[string](configuration.UnleashGithubRepo)
+	if token == "" || repo == "" || configuration.Get[bool](configuration.UnleashDryRunKey) || ctx.Err() != nil {
+		return
+	}
+
+	client, err := github.NewClient(repo, token)
+	if err != nil {
+		log.Errorf("impossible to publish on GitHub: %s\n", err)
+
+		return
+	}
+	diffRef := configuration.Get[string](configuration.UnleashDiffRef)
+	publisher := github.NewPublisher(client, diffRef, github.WithDir(filepath.Join(mod.Root, mod.CallingDir)))
+	if err := publisher.Publish(ctx, comments); err != nil {
+		log.Errorf("impossible to publish on GitHub: %s\n", err)
+	}
+}
+
 func setFlagsOnCmd(cmd *cobra.Command) error {
 	cmd.Flags().SortFlags = false
 	cmd.Flags().SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
@@ -209,20 +372,40 @@ func setFlagsOnCmd(cmd *cobra.Command) error {
 
 	fls := []*flags.Flag{
 		{Name: paramDryRun, CfgKey: configuration.UnleashDryRunKey, Shorthand: "d", DefaultV: false, Usage: "find mutations but do not executes tests"},
-		{Name: paramOutputStatuses, CfgKey: configuration.UnleashOutputStatusesKey, Shorthand: "S", DefaultV: "", Usage: "print only statuses from this flag, allowed values - 'lctkvsr'"},
+		{Name: paramOutputStatuses, CfgKey: configuration.UnleashOutputStatusesKey, Shorthand: "S", DefaultV: "", Usage: "print only statuses from this flag, allowed values - 'lctkvsrie'"},
 		{Name: paramBuildTags, CfgKey: configuration.UnleashTagsKey, Shorthand: "t", DefaultV: "", Usage: "a comma-separated list of build tags"},
 		{Name: paramCoverPackages, CfgKey: configuration.UnleashCoverPkgKey, DefaultV: "", Usage: "a comma-separated list of package patterns"},
+		{Name: paramPerTestCoverage, CfgKey: configuration.UnleashPerTestCoverageKey, DefaultV: false, Usage: "gather the coverage of each test to run only the tests covering each mutant"},
 		{Name: paramDiff, CfgKey: configuration.UnleashDiffRef, Shorthand: "D", DefaultV: "", Usage: "diff branch or commit"},
-		{Name: paramGithubToken, CfgKey: configuration.UnleashGithubToken, Shorthand: "", DefaultV: "", Usage: "Github token"},
-		{Name: paramGithubRepo, CfgKey: configuration.UnleashGithubRepo, Shorthand: "", DefaultV: "", Usage: "Github repo"},
+		{Name: paramGithubToken, CfgKey: configuration.UnleashGithubToken, Shorthand: "", DefaultV: "", Usage: "GitHub token used to publish LIVED mutants as a pull request review"},
+		{Name: paramGithubRepo, CfgKey: configuration.UnleashGithubRepo, Shorthand: "", DefaultV: "", Usage: "GitHub repository, in the owner/name format, of the pull request to review"},
+		{Name: paramCache, CfgKey: configuration.UnleashCacheKey, DefaultV: "", Usage: "the file in which to cache the results, to reuse them in the next runs"},
+		{Name: paramCacheInvalidate, CfgKey: configuration.UnleashCacheInvalidateKey, DefaultV: false, Usage: "discard the cached results before running"},
 		{Name: paramOutput, CfgKey: configuration.UnleashOutputKey, Shorthand: "o", DefaultV: "", Usage: "set the output file for machine readable results"},
+		{Name: paramOutputHTML, CfgKey: configuration.UnleashOutputHTMLKey, DefaultV: "", Usage: "set the folder in which to write the HTML report"},
+		{Name: paramOutputStryker, CfgKey: configuration.UnleashOutputStrykerKey, DefaultV: "", Usage: "set the output file for the mutation-testing-report-schema JSON report"},
+		{Name: paramOutputSARIF, CfgKey: configuration.UnleashOutputSARIFKey, DefaultV: "", Usage: "set the output file for the SARIF report of the LIVED and NOT COVERED mutants"},
+		{Name: paramOutputJUnit, CfgKey: configuration.UnleashOutputJUnitKey, DefaultV: "", Usage: "set the output file for the JUnit XML report"},
 		{Name: paramIntegrationMode, CfgKey: configuration.UnleashIntegrationMode, Shorthand: "i", DefaultV: false, Usage: "makes Gremlins run the complete test suite for each mutation"},
+		{Name: paramOverlay, CfgKey: configuration.UnleashOverlayKey, DefaultV: false, Usage: "pass the mutated files to go test with -overlay instead of copying the module for each worker"},
+		{Name: paramSchemata, CfgKey: configuration.UnleashSchemataKey, DefaultV: false, Usage: "build the tests of each package once with all its mutants, and switch them at runtime"},
+		{Name: paramBinaryTimeouts, CfgKey: configuration.UnleashBinaryTimeoutsKey, DefaultV: false, Usage: "time out the tests of each mutant on the run time of the test binary of its package, built with go test -c"},
+		{Name: paramEquivalentFilter, CfgKey: configuration.UnleashEquivalentFilterKey, DefaultV: true, Usage: "mark as EQUIVALENT the LIVED mutants compiling to the same code as the original"},
 		{Name: paramExcludeFiles, CfgKey: configuration.UnleashExcludeFiles, Shorthand: "E", DefaultV: []string{}, Usage: "exclude files from Gremlins run by filepath regexp"},
+		{Name: paramExcludePackages, CfgKey: configuration.UnleashExcludePackages, DefaultV: []string{}, Usage: "exclude packages from Gremlins run by import path pattern"},
+		{Name: paramExcludeFunctions, CfgKey: configuration.UnleashExcludeFunctions, DefaultV: []string{}, Usage: "exclude functions and methods from Gremlins run by name pattern, such as (*Server).String"},
+		{Name: paramIncludeGenerated, CfgKey: configuration.UnleashIncludeGeneratedKey, DefaultV: false, Usage: "include the generated files, which are skipped by default"},
+		{Name: paramAridPresets, CfgKey: configuration.UnleashAridPresetsKey, DefaultV: []string{}, Usage: "the presets of arid calls to use, among slog, zap, zerolog, logrus, glog and klog"},
+		{Name: paramAridFunctions, CfgKey: configuration.UnleashAridFunctionsKey, DefaultV: []string{}, Usage: "the names of the functions and methods whose calls are not mutated"},
+		{Name: paramAridPackages, CfgKey: configuration.UnleashAridPackagesKey, DefaultV: []string{}, Usage: "the import paths of the packages whose calls are not mutated"},
+		{Name: paramAridReceivers, CfgKey: configuration.UnleashAridReceiversKey, DefaultV: []string{}, Usage: "the types whose methods calls are not mutated, such as trace.Span"},
 		{Name: paramThresholdEfficacy, CfgKey: configuration.UnleashThresholdEfficacyKey, DefaultV: float64(0), Usage: "threshold for code-efficacy percent"},
 		{Name: paramThresholdMCoverage, CfgKey: configuration.UnleashThresholdMCoverageKey, DefaultV: float64(0), Usage: "threshold for mutant-coverage percent"},
 		{Name: paramWorkers, CfgKey: configuration.UnleashWorkersKey, DefaultV: 0, Usage: "the number of workers to use in mutation testing"},
 		{Name: paramTestCPU, CfgKey: configuration.UnleashTestCPUKey, DefaultV: 0, Usage: "the number of CPUs to allow each test run to use"},
 		{Name: paramTimeoutCoefficient, CfgKey: configuration.UnleashTimeoutCoefficientKey, DefaultV: 0, Usage: "the coefficient by which the timeout is increased"},
+		{Name: paramTimeoutFloor, CfgKey: configuration.UnleashTimeoutFloorKey, DefaultV: 0, Usage: "the shortest timeout in seconds of the tests of a package (0 = 5s)"},
+		{Name: paramTimeoutCeiling, CfgKey: configuration.UnleashTimeoutCeilingKey, DefaultV: 0, Usage: "the longest timeout in seconds of the tests of a package (0 = none)"},
 	}
 
 	for _, f := range fls {
@@ -50,6 +50,41 @@ func TestUnleash(t *testing.T) {
 			flagType: "bool",
 			defValue: "true",
 		},
+		{
+			name:     "boolean-literal",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "cache",
+			flagType: "string",
+			defValue: "",
+		},
+		{
+			name:     "cache-invalidate",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "call-zero-value",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "channel-capacity",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "condition-false",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "condition-true",
+			flagType: "bool",
+			defValue: "false",
+		},
 		{
 			name:     "conditionals-boundary",
 			flagType: "bool",
@@ -71,6 +106,11 @@ func TestUnleash(t *testing.T) {
 			flagType:  "string",
 			defValue:  "",
 		},
+		{
+			name:     "drop-variadic",
+			flagType: "bool",
+			defValue: "false",
+		},
 		{
 			name:      "dry-run",
 			shorthand: "d",
@@ -119,17 +159,112 @@ func TestUnleash(t *testing.T) {
 			flagType: "bool",
 			defValue: "true",
 		},
+		{
+			name:     "numeric-literal",
+			flagType: "bool",
+			defValue: "false",
+		},
 		{
 			name:      "output",
 			shorthand: "o",
 			flagType:  "string",
 			defValue:  "",
 		},
+		{
+			name:     "output-html",
+			flagType: "string",
+			defValue: "",
+		},
+		{
+			name:     "output-junit",
+			flagType: "string",
+			defValue: "",
+		},
+		{
+			name:     "output-sarif",
+			flagType: "string",
+			defValue: "",
+		},
+		{
+			name:     "output-stryker",
+			flagType: "string",
+			defValue: "",
+		},
+		{
+			name:     "per-test-coverage",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "remove-close",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "remove-error-check",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "remove-lock",
+			flagType: "bool",
+			defValue: "false",
+		},
 		{
 			name:     "remove-self-assignments",
 			flagType: "bool",
 			defValue: "false",
 		},
+		{
+			name:     "remove-waitgroup",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "return-empty-collection",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "return-error",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "return-negate-bool",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "return-nil-error",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "return-zero-value",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "swallow-error",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "string-literal",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "swap-arguments",
+			flagType: "bool",
+			defValue: "false",
+		},
+		{
+			name:     "sync-goroutine",
+			flagType: "bool",
+			defValue: "false",
+		},
 		{
 			name:      "tags",
 			shorthand: "t",
@@ -156,6 +291,11 @@ func TestUnleash(t *testing.T) {
 			flagType: "int",
 			defValue: "0",
 		},
+		{
+			name:     "unwrap-error",
+			flagType: "bool",
+			defValue: "false",
+		},
 		{
 			name:     "workers",
 			flagType: "int",
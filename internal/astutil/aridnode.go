@@ -1,6 +1,11 @@
 package astutil
 
-import "go/ast"
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/types/typeutil"
+)
 
 var loggerIdentifiers = map[string]bool{
 	"log":           true,
@@ -23,7 +28,16 @@ var loggerFuncIdentifiers = map[string]bool{
 	"Fatal":  true,
 }
 
-func IsAridNode(node ast.Node) bool {
+// IsAridNode checks if the node is arid, meaning that mutating it is not
+// interesting, like logging statements or blocks that contain only them.
+//
+// When info is not nil, the calls are resolved by type and matched against
+// the import paths of the AridPresets in use and the AridRules set with
+// SetAridRules. If the call can't be resolved, the identifiers names are
+// used instead. The deferred calls are arid like the other statements, the
+// assignments only when all their values are calls matching the AridRules,
+// as the logging packages also have functions whose results are used.
+func IsAridNode(node ast.Node, info *types.Info) bool {
 	if node == nil {
 		return true
 	}
@@ -31,37 +45,41 @@ func IsAridNode(node ast.Node) bool {
 	// Base case
 	switch n := node.(type) {
 	case *ast.ExprStmt:
-		if isLoggerStmt(node) || isLoggerFuncStmt(node) {
+		if call, ok := n.X.(*ast.CallExpr); ok && isLoggerCall(call, info) {
 			return true
 		}
 
-		return IsAridNode(n.X)
+		return IsAridNode(n.X, info)
+	case *ast.DeferStmt:
+		return isLoggerCall(n.Call, info)
+	case *ast.AssignStmt:
+		return isAridAssign(n, info)
 	case *ast.BlockStmt:
 		allChildrenArid := true
 		for _, s := range n.List {
-			if !IsAridNode(s) {
+			if !IsAridNode(s, info) {
 				allChildrenArid = false
 				break
 			}
 		}
 		return allChildrenArid
 	case *ast.IfStmt:
-		return IsAridNode(n.Body) && IsAridNode(n.Else)
+		return IsAridNode(n.Body, info) && IsAridNode(n.Else, info)
 	case *ast.CallExpr:
-		return IsAridNode(n.Fun)
+		return IsAridNode(n.Fun, info)
 	case *ast.Ident:
 		if n.Obj == nil {
 			return true
 		}
 		if funDecl, ok := (n.Obj.Decl).(*ast.FuncDecl); ok {
-			return IsAridNode(funDecl)
+			return IsAridNode(funDecl, info)
 		}
 	case *ast.FuncDecl:
-		return IsAridNode(n.Body)
+		return IsAridNode(n.Body, info)
 	case *ast.CaseClause:
 		allChildrenArid := true
 		for _, s := range n.Body {
-			if !IsAridNode(s) {
+			if !IsAridNode(s, info) {
 				allChildrenArid = false
 				break
 			}
@@ -72,12 +90,64 @@ func IsAridNode(node ast.Node) bool {
 	return false
 }
 
+func isLoggerCall(call *ast.CallExpr, info *types.Info) bool {
+	if fn, ok := callee(call, info); ok {
+		return aridRules.isAridFunc(fn)
+	}
+
+	return isLoggerStmt(call) || isLoggerFuncStmt(call) || aridRules.isAridCall(call)
+}
+
+// isAridAssign checks if all the values of the assignment are calls matching
+// the AridRules, like the spans started by a tracer.
+func isAridAssign(as *ast.AssignStmt, info *types.Info) bool {
+	if len(as.Rhs) == 0 {
+		return false
+	}
+	for _, rhs := range as.Rhs {
+		call, ok := rhs.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		if fn, ok := callee(call, info); ok {
+			if !aridAssigns.isAridFunc(fn) {
+				return false
+			}
+
+			continue
+		}
+		if !aridAssigns.isAridCall(call) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// callee resolves the function or method called, if info is not nil.
+func callee(call *ast.CallExpr, info *types.Info) (*types.Func, bool) {
+	if info == nil {
+		return nil, false
+	}
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+
+	return fn, ok
+}
+
 func isLoggerStmt(es ast.Node) bool {
+	_, found := loggerIdentifiers[firstIdent(es)]
+
+	return found
+}
+
+// firstIdent returns the name of the first identifier of the node, which
+// is the package or the variable a call is made on.
+func firstIdent(es ast.Node) string {
 	firstIdent := ""
 	ast.Inspect(es, func(n ast.Node) bool {
 		if ident, ok := n.(*ast.Ident); ok {
-			// Since it's a depth first traversal, first identifier should be
-			// one of loggerIdentifiers for the statement to be a logger stmt.
+			// Since it's a depth first traversal, the first identifier is
+			// the leftmost one of the statement.
 			if firstIdent != "" {
 				// If we've already found our candidate don't recurse further
 				return false
@@ -92,9 +162,7 @@ func isLoggerStmt(es ast.Node) bool {
 		return true
 	})
 
-	_, found := loggerIdentifiers[firstIdent]
-
-	return found
+	return firstIdent
 }
 
 func isLoggerFuncStmt(es ast.Node) bool {
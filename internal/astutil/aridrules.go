@@ -0,0 +1,185 @@
+package astutil
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path"
+	"strings"
+)
+
+// AridRules are the rules making calls arid, in addition to the calls of
+// the default AridPresets.
+type AridRules struct {
+	// Funcs are the names of the functions and methods whose calls are arid.
+	Funcs []string
+	// Packages are the import paths of the packages whose functions and
+	// methods calls are arid.
+	Packages []string
+	// Receivers are the types whose methods calls are arid, written as
+	// Type, pkg.Type or import/path.Type.
+	Receivers []string
+}
+
+// AridPresets are the built-in AridRules of the common logging libraries.
+// They are the only logging calls matched by type.
+var AridPresets = map[string]AridRules{
+	"log": {
+		Packages: []string{"fmt", "log"},
+	},
+	"slog": {
+		Packages: []string{"log/slog"},
+	},
+	"zap": {
+		Packages: []string{"go.uber.org/zap", "go.uber.org/zap/zapcore"},
+	},
+	"zerolog": {
+		Packages: []string{"github.com/rs/zerolog", "github.com/rs/zerolog/log"},
+	},
+	"logrus": {
+		Packages: []string{"github.com/sirupsen/logrus"},
+	},
+	"glog": {
+		Packages: []string{"github.com/golang/glog"},
+	},
+	"klog": {
+		Packages: []string{"k8s.io/klog", "k8s.io/klog/v2"},
+	},
+}
+
+// defaultAridPresets are the AridPresets always in use, the logging of the
+// standard library.
+var defaultAridPresets = []string{"log"}
+
+// aridSet is the lookup form of the configured AridRules.
+type aridSet struct {
+	funcs     map[string]bool
+	packages  map[string]bool
+	names     map[string]bool
+	receivers map[string]bool
+}
+
+// aridRules are the rules of the calls whose results are not used, and
+// aridAssigns the ones of the assigned calls, which don't include the
+// presets.
+var (
+	aridRules   = newAridSet(presetRules(defaultAridPresets)...)
+	aridAssigns aridSet
+)
+
+// SetAridRules replaces the configured AridRules with the given ones and
+// the ones of the presets. It is meant to be called once, before the
+// code is walked.
+func SetAridRules(presets []string, rules AridRules) error {
+	for _, p := range presets {
+		if _, ok := AridPresets[p]; !ok {
+			return fmt.Errorf("unknown arid preset %q", p)
+		}
+	}
+	all := append(presetRules(defaultAridPresets), presetRules(presets)...)
+	aridRules = newAridSet(append(all, rules)...)
+	aridAssigns = newAridSet(rules)
+
+	return nil
+}
+
+// ResetAridRules removes the configured AridRules, leaving the default
+// AridPresets.
+func ResetAridRules() {
+	aridRules = newAridSet(presetRules(defaultAridPresets)...)
+	aridAssigns = aridSet{}
+}
+
+func presetRules(presets []string) []AridRules {
+	rules := make([]AridRules, 0, len(presets))
+	for _, p := range presets {
+		rules = append(rules, AridPresets[p])
+	}
+
+	return rules
+}
+
+func newAridSet(rules ...AridRules) aridSet {
+	set := aridSet{
+		funcs:     make(map[string]bool),
+		packages:  make(map[string]bool),
+		names:     make(map[string]bool),
+		receivers: make(map[string]bool),
+	}
+	for _, r := range rules {
+		for _, f := range r.Funcs {
+			set.funcs[f] = true
+		}
+		for _, p := range r.Packages {
+			set.packages[p] = true
+			set.names[packageName(p)] = true
+		}
+		for _, t := range r.Receivers {
+			set.receivers[t] = true
+		}
+	}
+
+	return set
+}
+
+// packageName guesses the name of the package from its import path, the
+// last element skipping the major version suffix.
+func packageName(importPath string) string {
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = path.Base(path.Dir(importPath))
+	}
+
+	return name
+}
+
+// isAridFunc checks the resolved callee against the configured AridRules.
+func (s aridSet) isAridFunc(fn *types.Func) bool {
+	if s.funcs[fn.Name()] {
+		return true
+	}
+	if fn.Pkg() != nil && s.packages[fn.Pkg().Path()] {
+		return true
+	}
+	if len(s.receivers) == 0 {
+		return false
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return false
+	}
+	recv := sig.Recv().Type()
+	if p, ok := recv.(*types.Pointer); ok {
+		recv = p.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	if s.receivers[obj.Name()] {
+		return true
+	}
+
+	return obj.Pkg() != nil &&
+		(s.receivers[obj.Pkg().Name()+"."+obj.Name()] || s.receivers[obj.Pkg().Path()+"."+obj.Name()])
+}
+
+// isAridCall checks the call against the configured AridRules when it
+// can't be resolved, using the name of the called function and the first
+// identifier, which is the package of a qualified call. The receivers
+// can't be checked without the type information.
+func (s aridSet) isAridCall(call *ast.CallExpr) bool {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		if s.funcs[fun.Name] {
+			return true
+		}
+	case *ast.SelectorExpr:
+		if s.funcs[fun.Sel.Name] {
+			return true
+		}
+	}
+
+	return len(s.names) != 0 && s.names[firstIdent(call)]
+}
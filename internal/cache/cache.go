@@ -0,0 +1,204 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// version is the version of the cache file format. Files with a different
+// version are discarded.
+const version = 2
+
+// Key is the identity of a mutant. If none of its parts change between two
+// runs, the mutant is expected to have the same verdict.
+type Key struct {
+	// FileHash is the hash of the source file containing the mutant.
+	FileHash string
+	// TestsHash is the hash of the test files of the package containing
+	// the mutant.
+	TestsHash string
+	Type      mutator.Type
+	Line      int
+	Column    int
+	// Replacement is the code put in place of the original one, which tells
+	// apart the mutants of the same type at the same position.
+	Replacement string
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%s:%s:%s:%d:%d:%q", k.FileHash, k.TestsHash, k.Type, k.Line, k.Column, k.Replacement)
+}
+
+// Cache is an on-disk store of the verdicts of the mutants, which allows to
+// reuse the KILLED and LIVED statuses of the mutants whose source and tests
+// did not change since the previous run.
+//
+// Only the entries looked up or stored during the run are saved, so the
+// ones of the mutants which no longer exist, like the ones of the previous
+// versions of a file, are dropped.
+//
+// It is safe for concurrent use.
+type Cache struct {
+	entries     map[string]string
+	used        map[string]bool
+	path        string
+	fingerprint string
+	mutex       sync.RWMutex
+}
+
+type cacheFile struct {
+	Entries     map[string]string `json:"entries"`
+	Fingerprint string            `json:"fingerprint"`
+	Version     int               `json:"version"`
+}
+
+// Load reads the cache from the given path.
+//
+// The fingerprint identifies the configuration of the run which can
+// influence the verdicts, such as the build tags. If the stored fingerprint
+// is different, or invalidate is true, the Cache starts empty. A missing
+// file is not an error, the Cache will be created on Save.
+func Load(path, fingerprint string, invalidate bool) (*Cache, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cache{
+		path:        absPath,
+		fingerprint: fingerprint,
+		entries:     make(map[string]string),
+		used:        make(map[string]bool),
+	}
+	if invalidate {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(absPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("impossible to read cache: %w", err)
+	}
+	var cf cacheFile
+	if err := json.Unmarshal(data, &cf); err != nil {
+		return nil, fmt.Errorf("impossible to parse cache %q: %w", absPath, err)
+	}
+	if cf.Version != version || cf.Fingerprint != fingerprint || cf.Entries == nil {
+		return c, nil
+	}
+	c.entries = cf.Entries
+
+	return c, nil
+}
+
+// Get returns the cached mutator.Status for the Key, if present.
+func (c *Cache) Get(k Key) (mutator.Status, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := k.String()
+	status, ok := c.entries[key]
+	if ok {
+		c.used[key] = true
+	}
+
+	switch status {
+	case mutator.Killed.String():
+		return mutator.Killed, true
+	case mutator.Lived.String():
+		return mutator.Lived, true
+	case mutator.Equivalent.String():
+		return mutator.Equivalent, true
+	default:
+		return 0, false
+	}
+}
+
+// Set stores the mutator.Status for the Key. Only KILLED, LIVED and
+// EQUIVALENT are stored, since the other statuses depend on the environment
+// of the run (TIMED OUT) or are not verdicts at all.
+func (c *Cache) Set(k Key, s mutator.Status) {
+	if s != mutator.Killed && s != mutator.Lived && s != mutator.Equivalent {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := k.String()
+	c.entries[key] = s.String()
+	c.used[key] = true
+}
+
+// Len returns the number of entries in the Cache.
+func (c *Cache) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return len(c.entries)
+}
+
+// Save writes the entries used during the run to disk. The file is first
+// written in a temporary file, and then moved in place, so that an
+// interrupted run doesn't leave a corrupted cache behind.
+func (c *Cache) Save() error {
+	c.mutex.RLock()
+	entries := make(map[string]string, len(c.used))
+	for k := range c.used {
+		entries[k] = c.entries[k]
+	}
+	c.mutex.RUnlock()
+	data, err := json.Marshal(cacheFile{
+		Version:     version,
+		Fingerprint: c.fingerprint,
+		Entries:     entries,
+	})
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("impossible to create cache folder: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("impossible to write cache: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("impossible to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("impossible to write cache: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
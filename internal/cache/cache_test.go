@@ -0,0 +1,186 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/singhnishant94/gremlins/internal/cache"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const fingerprint = "tags=;integration=false"
+
+var (
+	killedKey = cache.Key{FileHash: "file", TestsHash: "tests", Type: mutator.ConditionalsBoundary, Line: 10, Column: 3}
+	livedKey  = cache.Key{FileHash: "file", TestsHash: "tests", Type: mutator.ConditionalsNegation, Line: 10, Column: 3}
+	equivKey  = cache.Key{FileHash: "file", TestsHash: "tests", Type: mutator.InvertAssignments, Line: 12, Column: 3}
+)
+
+func TestCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "gremlins.cache")
+	c, err := cache.Load(path, fingerprint, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Set(killedKey, mutator.Killed)
+	c.Set(livedKey, mutator.Lived)
+	c.Set(equivKey, mutator.Equivalent)
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name        string
+		key         cache.Key
+		fingerprint string
+		invalidate  bool
+		wantStatus  mutator.Status
+		wantFound   bool
+	}{
+		{
+			name:        "finds KILLED",
+			key:         killedKey,
+			fingerprint: fingerprint,
+			wantStatus:  mutator.Killed,
+			wantFound:   true,
+		},
+		{
+			name:        "finds LIVED",
+			key:         livedKey,
+			fingerprint: fingerprint,
+			wantStatus:  mutator.Lived,
+			wantFound:   true,
+		},
+		{
+			name:        "finds EQUIVALENT",
+			key:         equivKey,
+			fingerprint: fingerprint,
+			wantStatus:  mutator.Equivalent,
+			wantFound:   true,
+		},
+		{
+			name:        "doesn't find different tests",
+			key:         cache.Key{FileHash: "file", TestsHash: "changed", Type: mutator.ConditionalsBoundary, Line: 10, Column: 3},
+			fingerprint: fingerprint,
+		},
+		{
+			name:        "doesn't find different file",
+			key:         cache.Key{FileHash: "changed", TestsHash: "tests", Type: mutator.ConditionalsBoundary, Line: 10, Column: 3},
+			fingerprint: fingerprint,
+		},
+		{
+			name:        "doesn't find different replacement",
+			key:         cache.Key{FileHash: "file", TestsHash: "tests", Type: mutator.ConditionalsBoundary, Line: 10, Column: 3, Replacement: "<="},
+			fingerprint: fingerprint,
+		},
+		{
+			name:        "is empty if the fingerprint changes",
+			key:         killedKey,
+			fingerprint: "tags=tag1;integration=false",
+		},
+		{
+			name:        "is empty if invalidated",
+			key:         killedKey,
+			fingerprint: fingerprint,
+			invalidate:  true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := cache.Load(path, tc.fingerprint, tc.invalidate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, ok := c.Get(tc.key)
+
+			if ok != tc.wantFound {
+				t.Fatalf("expected found to be %t, got %t", tc.wantFound, ok)
+			}
+			if ok && got != tc.wantStatus {
+				t.Errorf("expected status %s, got %s", tc.wantStatus, got)
+			}
+		})
+	}
+}
+
+func TestCacheSavesOnlyUsedEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gremlins.cache")
+	c, _ := cache.Load(path, fingerprint, false)
+	c.Set(killedKey, mutator.Killed)
+	c.Set(livedKey, mutator.Lived)
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second run finds the KILLED mutant, and stores a new one, the
+	// LIVED mutant is gone.
+	c, _ = cache.Load(path, fingerprint, false)
+	if _, ok := c.Get(killedKey); !ok {
+		t.Fatal("expected to find the KILLED mutant")
+	}
+	c.Set(equivKey, mutator.Equivalent)
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ = cache.Load(path, fingerprint, false)
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", c.Len())
+	}
+	if _, ok := c.Get(livedKey); ok {
+		t.Error("expected the LIVED mutant to be pruned")
+	}
+}
+
+func TestCacheStoresOnlyVerdicts(t *testing.T) {
+	c, _ := cache.Load(filepath.Join(t.TempDir(), "gremlins.cache"), fingerprint, false)
+
+	for _, s := range []mutator.Status{mutator.NotCovered, mutator.Runnable, mutator.Skipped, mutator.NotViable, mutator.TimedOut} {
+		c.Set(killedKey, s)
+	}
+
+	if c.Len() != 0 {
+		t.Errorf("expected no entries, got %d", c.Len())
+	}
+}
+
+func TestCacheMissingFileIsEmpty(t *testing.T) {
+	c, err := cache.Load(filepath.Join(t.TempDir(), "missing"), fingerprint, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Len() != 0 {
+		t.Errorf("expected no entries, got %d", c.Len())
+	}
+}
+
+func TestCacheCorruptedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gremlins.cache")
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cache.Load(path, fingerprint, false); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -36,18 +36,39 @@ const (
 	UnleashDryRunKey             = "unleash.dry-run"
 	UnleashOutputStatusesKey     = "unleash.output-statuses"
 	UnleashOutputKey             = "unleash.output"
+	UnleashOutputHTMLKey         = "unleash.output-html"
+	UnleashOutputStrykerKey      = "unleash.output-stryker"
+	UnleashOutputSARIFKey        = "unleash.output-sarif"
+	UnleashOutputJUnitKey        = "unleash.output-junit"
 	UnleashTagsKey               = "unleash.tags"
 	UnleashCoverPkgKey           = "unleash.coverpkg"
+	UnleashPerTestCoverageKey    = "unleash.per-test-coverage"
 	UnleashWorkersKey            = "unleash.workers"
 	UnleashTestCPUKey            = "unleash.test-cpu"
 	UnleashTimeoutCoefficientKey = "unleash.timeout-coefficient"
+	UnleashTimeoutFloorKey       = "unleash.timeout-floor"
+	UnleashTimeoutCeilingKey     = "unleash.timeout-ceiling"
 	UnleashIntegrationMode       = "unleash.integration"
+	UnleashOverlayKey            = "unleash.overlay"
+	UnleashSchemataKey           = "unleash.schemata"
+	UnleashBinaryTimeoutsKey     = "unleash.binary-timeouts"
+	UnleashEquivalentFilterKey   = "unleash.equivalent-filter"
 	UnleashExcludeFiles          = "unleash.exclude-files"
+	UnleashExcludePackages       = "unleash.exclude-packages"
+	UnleashExcludeFunctions      = "unleash.exclude-functions"
+	UnleashIncludeGeneratedKey   = "unleash.include-generated"
 	UnleashDiffRef               = "unleash.diff"
 	UnleashGithubToken           = "unleash.github-token"
 	UnleashGithubRepo            = "unleash.github-repo"
+	UnleashCacheKey              = "unleash.cache"
+	UnleashCacheInvalidateKey    = "unleash.cache-invalidate"
 	UnleashThresholdEfficacyKey  = "unleash.threshold.efficacy"
 	UnleashThresholdMCoverageKey = "unleash.threshold.mutant-coverage"
+	UnleashRulesKey              = "unleash.rules"
+	UnleashAridPresetsKey        = "unleash.arid.presets"
+	UnleashAridFunctionsKey      = "unleash.arid.functions"
+	UnleashAridPackagesKey       = "unleash.arid.packages"
+	UnleashAridReceiversKey      = "unleash.arid.receivers"
 )
 
 const (
@@ -32,6 +32,28 @@ var mutationEnabled = map[mutator.Type]bool{
 	mutator.InvertLoopCtrl:           false,
 	mutator.InvertNegatives:          true,
 	mutator.RemoveSelfAssignments:    false,
+	mutator.RemoveStatement:          true,
+	mutator.ReturnZeroValue:          false,
+	mutator.ReturnNegateBool:         false,
+	mutator.ReturnNilError:           false,
+	mutator.ReturnError:              false,
+	mutator.ReturnEmptyCollection:    false,
+	mutator.RemoveErrorCheck:         false,
+	mutator.SwallowError:             false,
+	mutator.UnwrapError:              false,
+	mutator.ConditionTrue:            false,
+	mutator.ConditionFalse:           false,
+	mutator.NumericLiteral:           false,
+	mutator.StringLiteral:            false,
+	mutator.BooleanLiteral:           false,
+	mutator.CallZeroValue:            false,
+	mutator.SwapArguments:            false,
+	mutator.DropVariadic:             false,
+	mutator.RemoveLock:               false,
+	mutator.SyncGoroutine:            false,
+	mutator.ChannelCapacity:          false,
+	mutator.RemoveClose:              false,
+	mutator.RemoveWaitGroup:          false,
 }
 
 // IsDefaultEnabled returns the default enabled/disabled state of the mutation.
@@ -40,3 +62,10 @@ var mutationEnabled = map[mutator.Type]bool{
 func IsDefaultEnabled(mt mutator.Type) bool {
 	return mutationEnabled[mt]
 }
+
+// SetDefaultEnabled sets the default enabled/disabled state of a registered
+// mutation. Like mutator.Register, it is meant to be called before the
+// flags are set.
+func SetDefaultEnabled(mt mutator.Type, enabled bool) {
+	mutationEnabled[mt] = enabled
+}
@@ -74,6 +74,102 @@ func TestMutantDefaultStatus(t *testing.T) {
 			mutantType: mutator.RemoveSelfAssignments,
 			expected:   false,
 		},
+		{
+			mutantType: mutator.RemoveBinaryExpressionLeft,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.RemoveBinaryExpressionRight,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.RemoveStatement,
+			expected:   true,
+		},
+		{
+			mutantType: mutator.ReturnZeroValue,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.ReturnNegateBool,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.ReturnNilError,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.ReturnError,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.ReturnEmptyCollection,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.RemoveErrorCheck,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.SwallowError,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.UnwrapError,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.ConditionTrue,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.ConditionFalse,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.NumericLiteral,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.StringLiteral,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.BooleanLiteral,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.CallZeroValue,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.SwapArguments,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.DropVariadic,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.RemoveLock,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.SyncGoroutine,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.ChannelCapacity,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.RemoveClose,
+			expected:   false,
+		},
+		{
+			mutantType: mutator.RemoveWaitGroup,
+			expected:   false,
+		},
 	}
 
 	for _, tc := range testCases {
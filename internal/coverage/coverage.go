@@ -17,6 +17,9 @@
 package coverage
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -35,9 +38,12 @@ import (
 
 // Result contains the Profile generated by the coverage and the time
 // it took to generate the coverage report.
+// Packages contains the time the tests of each package took to run, indexed
+// by import path, without the time spent building them.
 type Result struct {
-	Profile Profile
-	Elapsed time.Duration
+	Profile  Profile
+	Packages map[string]time.Duration
+	Elapsed  time.Duration
 }
 
 // Coverage is responsible for executing a Go test with coverage via the Run() method,
@@ -99,7 +105,7 @@ func (c *Coverage) Run() (Result, error) {
 	if err := c.downloadModules(); err != nil {
 		return Result{}, fmt.Errorf("impossible to download modules: %w", err)
 	}
-	elapsed, err := c.executeCoverage()
+	elapsed, packages, err := c.executeCoverage()
 	if err != nil {
 		return Result{}, fmt.Errorf("impossible to executeCoverage coverage: %w", err)
 	}
@@ -109,7 +115,7 @@ func (c *Coverage) Run() (Result, error) {
 		return Result{}, fmt.Errorf("an error occurred while generating coverage profile: %w", err)
 	}
 
-	return Result{Profile: profile, Elapsed: elapsed}, nil
+	return Result{Profile: profile, Packages: packages, Elapsed: elapsed}, nil
 }
 
 func (c *Coverage) profile() (Profile, error) {
@@ -140,7 +146,10 @@ func (c *Coverage) downloadModules() error {
 	return cmd.Run()
 }
 
-func (c *Coverage) executeCoverage() (time.Duration, error) {
+// executeCoverage runs the tests with coverage, returning the time the run
+// took and the time the tests of each package took, from the events of
+// go test -json.
+func (c *Coverage) executeCoverage() (time.Duration, map[string]time.Duration, error) {
 	args := []string{"test"}
 	if c.buildTags != "" {
 		args = append(args, "-tags", c.buildTags)
@@ -149,17 +158,50 @@ func (c *Coverage) executeCoverage() (time.Duration, error) {
 		args = append(args, "-coverpkg", c.coverPkg)
 	}
 
-	args = append(args, "-cover", "-coverprofile", c.filePath(), c.scanPath())
+	args = append(args, "-cover", "-coverprofile", c.filePath(), "-json", c.scanPath())
 	cmd := c.cmdContext("go", args...)
 
 	start := time.Now()
-	if out, err := cmd.CombinedOutput(); err != nil {
-		log.Infof("\n%s\n", string(out))
+	out, err := cmd.CombinedOutput()
+	elapsed := time.Since(start)
+	packages, output, scanErr := parseEvents(out)
+	if err != nil {
+		log.Infof("\n%s\n", output)
+
+		return 0, nil, err
+	}
+	if scanErr != nil {
+		return 0, nil, scanErr
+	}
 
-		return 0, err
+	return elapsed, packages, nil
+}
+
+// parseEvents reads the output of go test -json, returning the time the
+// tests of each package took and the output as go test would print it
+// without -json. The lines which are not events, like the build errors,
+// are kept as they are. The output of a test can make long events, so
+// the lines are read with a larger buffer than the default one.
+func parseEvents(out []byte) (map[string]time.Duration, string, error) {
+	packages := make(map[string]time.Duration)
+	var output strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var e testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			output.Write(scanner.Bytes())
+			output.WriteString("\n")
+
+			continue
+		}
+		output.WriteString(e.Output)
+		if e.Test == "" && (e.Action == "pass" || e.Action == "fail") {
+			packages[e.Package] = time.Duration(e.Elapsed * float64(time.Second))
+		}
 	}
 
-	return time.Since(start), nil
+	return packages, output.String(), scanner.Err()
 }
 
 func (c *Coverage) scanPath() string {
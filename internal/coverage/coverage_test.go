@@ -22,6 +22,7 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/viper"
@@ -94,7 +95,7 @@ func TestCoverageRun(t *testing.T) {
 			_, _ = cov.Run()
 
 			firstWant := "go mod download"
-			secondWant := fmt.Sprintf("go test -tags tag1 tag2 -coverpkg %s -cover -coverprofile %v %s",
+			secondWant := fmt.Sprintf("go test -tags tag1 tag2 -coverpkg %s -cover -coverprofile %v -json %s",
 				coverpkg, wantFilePath, tc.wantPath)
 
 			if len(holder.events) != 2 {
@@ -176,6 +177,28 @@ func TestCoverageParsesOutput(t *testing.T) {
 	}
 }
 
+func TestCoveragePackagesElapsed(t *testing.T) {
+	mod := gomodule.GoModule{
+		Name:       "example.com",
+		CallingDir: "path",
+	}
+	cov := coverage.NewWithCmd(fakeExecCommandEvents, "testdata/valid", mod)
+
+	got, err := cov.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The events of the tests and the packages without tests are ignored.
+	want := map[string]time.Duration{
+		"example.com/pkg1": 1500 * time.Millisecond,
+		"example.com/pkg2": 20 * time.Millisecond,
+	}
+	if !cmp.Equal(got.Packages, want) {
+		t.Error(cmp.Diff(want, got.Packages))
+	}
+}
+
 func TestParseOutputFail(t *testing.T) {
 	mod := gomodule.GoModule{
 		Name:       "example.com",
@@ -202,6 +225,21 @@ func TestCoverageProcessFailure(_ *testing.T) {
 	os.Exit(1) // skipcq: RVV-A0003
 }
 
+func TestCoverageProcessEvents(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+	fmt.Println(`{"Action":"output","Package":"example.com/pkg1","Test":"TestA","Output":"=== RUN   TestA\n"}`)
+	fmt.Println(`{"Action":"pass","Package":"example.com/pkg1","Test":"TestA","Elapsed":1.2}`)
+	fmt.Println(`{"Action":"pass","Package":"example.com/pkg1","Elapsed":1.5}`)
+	// An event longer than the default buffer of bufio.Scanner.
+	fmt.Printf("{\"Action\":\"output\",\"Package\":\"example.com/pkg2\",\"Output\":\"%s\\n\"}\n", strings.Repeat("x", 100*1024))
+	fmt.Println(`{"Action":"pass","Package":"example.com/pkg2","Elapsed":0.02}`)
+	fmt.Println(`{"Action":"skip","Package":"example.com/pkg3","Elapsed":0}`)
+	fmt.Println("go: downloading example.com/dep v1.0.0")
+	os.Exit(0) // skipcq: RVV-A0003
+}
+
 type execContext = func(name string, args ...string) *exec.Cmd
 
 func fakeExecCommandSuccess(got *commandHolder) execContext {
@@ -222,6 +260,20 @@ func fakeExecCommandSuccess(got *commandHolder) execContext {
 	}
 }
 
+func fakeExecCommandEvents(command string, args ...string) *exec.Cmd {
+	run := "-test.run=TestCoverageProcessSuccess"
+	if len(args) > 0 && args[0] == "test" {
+		run = "-test.run=TestCoverageProcessEvents"
+	}
+	cs := []string{run, "--", command}
+	cs = append(cs, args...)
+	// #nosec G204 - We are in tests, we don't care
+	cmd := exec.Command(os.Args[0], cs...)
+	cmd.Env = []string{"GO_TEST_PROCESS=1"}
+
+	return cmd
+}
+
 func fakeExecCommandFailure(run int) execContext {
 	var executed int
 
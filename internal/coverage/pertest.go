@@ -0,0 +1,199 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/singhnishant94/gremlins/internal/log"
+)
+
+// testNameRegexp matches the top level functions run by go test -run.
+var testNameRegexp = regexp.MustCompile(`^(Test|Fuzz|Example)\w*$`)
+
+// Test is a test function of a package.
+type Test struct {
+	Package string
+	Name    string
+}
+
+// TestsProfile holds the Profile covered by each test function.
+type TestsProfile map[Test]Profile
+
+// Tests returns the tests covering the token.Position, sorted by package
+// and name.
+func (tp TestsProfile) Tests(pos token.Position) []Test {
+	var tests []Test
+	for t, p := range tp {
+		if p.IsCovered(pos) {
+			tests = append(tests, t)
+		}
+	}
+	sort.Slice(tests, func(i, j int) bool {
+		if tests[i].Package != tests[j].Package {
+			return tests[i].Package < tests[j].Package
+		}
+
+		return tests[i].Name < tests[j].Name
+	})
+
+	return tests
+}
+
+// RunPerTest executes each test function on its own, gathering the
+// coverage of each of them. It must be called after Run. At most workers
+// tests are run in parallel, or as many as the CPUs if it is 0.
+//
+// It is much slower than Run, since each test is executed in a separate
+// go test process, but it allows to run only the tests that reach a
+// mutant.
+func (c *Coverage) RunPerTest(workers int) (TestsProfile, error) {
+	log.Infof("Gathering per-test coverage... ")
+	tests, err := c.listTests()
+	if err != nil {
+		return nil, fmt.Errorf("impossible to list tests: %w", err)
+	}
+
+	result := make(TestsProfile)
+	var mutex sync.Mutex
+	var firstErr error
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				t := tests[i]
+				profile, err := c.testCoverage(t, i)
+				mutex.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("impossible to gather coverage of %s in %s: %w", t.name, t.pkg, err)
+				}
+				key := Test{Package: t.pkg, Name: t.name}
+				result[key] = result[key].merge(profile)
+				mutex.Unlock()
+			}
+		}()
+	}
+	for i := range tests {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	log.Infof("done for %d tests\n", len(tests))
+
+	return result, nil
+}
+
+type testFunc struct {
+	pkg  string
+	name string
+}
+
+// testEvent is an event of go test -json. The Elapsed seconds are set on
+// the pass and fail events.
+type testEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Output  string
+	Elapsed float64
+}
+
+func (c *Coverage) listTests() ([]testFunc, error) {
+	args := []string{"test"}
+	if c.buildTags != "" {
+		args = append(args, "-tags", c.buildTags)
+	}
+	args = append(args, "-list", ".", "-json", c.scanPath())
+	cmd := c.cmdContext("go", args...)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []testFunc
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		var e testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		name := strings.TrimSpace(e.Output)
+		if e.Action != "output" || !testNameRegexp.MatchString(name) {
+			continue
+		}
+		tests = append(tests, testFunc{pkg: e.Package, name: name})
+	}
+
+	return tests, scanner.Err()
+}
+
+func (c *Coverage) testCoverage(t testFunc, n int) (Profile, error) {
+	fileName := filepath.Join(c.workDir, fmt.Sprintf("%s-%d", c.fileName, n))
+	args := []string{"test"}
+	if c.buildTags != "" {
+		args = append(args, "-tags", c.buildTags)
+	}
+	if c.coverPkg != "" {
+		args = append(args, "-coverpkg", c.coverPkg)
+	}
+	args = append(args, "-cover", "-coverprofile", fileName, "-run", fmt.Sprintf("^%s$", t.name), t.pkg)
+	cmd := c.cmdContext("go", args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%w\n%s", err, out)
+	}
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	return c.parse(f)
+}
+
+func (p Profile) merge(other Profile) Profile {
+	if p == nil {
+		p = make(Profile)
+	}
+	for fn, blocks := range other {
+		p[fn] = append(p[fn], blocks...)
+	}
+
+	return p
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package coverage_test
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/coverage"
+	"github.com/singhnishant94/gremlins/internal/gomodule"
+)
+
+const testsList = `{"Action":"start","Package":"example.com/path"}
+{"Action":"output","Package":"example.com/path","Output":"TestA\n"}
+{"Action":"output","Package":"example.com/path","Output":"ExampleB\n"}
+{"Action":"output","Package":"example.com/path","Output":"BenchmarkC\n"}
+{"Action":"output","Package":"example.com/path","Output":"ok  \texample.com/path\t0.002s\n"}
+{"Action":"pass","Package":"example.com/path"}
+{"Action":"output","Package":"example.com/path/sub","Output":"TestA\n"}
+{"Action":"output","Package":"example.com/path/other","Output":"?   \texample.com/path/other\t[no test files]\n"}
+`
+
+func TestRunPerTest(t *testing.T) {
+	mod := gomodule.GoModule{
+		Name:       "example.com",
+		CallingDir: "path",
+	}
+	cov := coverage.NewWithCmd(fakeExecCommandPerTest, t.TempDir(), mod)
+
+	got, err := cov.RunPerTest(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testA := coverage.Test{Package: "example.com/path", Name: "TestA"}
+	want := coverage.TestsProfile{
+		testA: {
+			"file1.go": {{StartLine: 10, StartCol: 2, EndLine: 11, EndCol: 16}},
+		},
+		{Package: "example.com/path/sub", Name: "TestA"}: {
+			"file2.go": {{StartLine: 20, StartCol: 2, EndLine: 21, EndCol: 16}},
+		},
+		{Package: "example.com/path", Name: "ExampleB"}: {
+			"file1.go": {{StartLine: 30, StartCol: 2, EndLine: 31, EndCol: 16}},
+		},
+	}
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	tests := got.Tests(token.Position{Filename: "file1.go", Line: 10, Column: 5})
+	if !cmp.Equal(tests, []coverage.Test{testA}) {
+		t.Errorf("expected TestA to cover the position, got %v", tests)
+	}
+}
+
+func TestRunPerTestFails(t *testing.T) {
+	mod := gomodule.GoModule{
+		Name:       "example.com",
+		CallingDir: "path",
+	}
+
+	t.Run("failure of: go test -list", func(t *testing.T) {
+		cov := coverage.NewWithCmd(fakeExecCommandFailure(0), t.TempDir(), mod)
+		if _, err := cov.RunPerTest(0); err == nil {
+			t.Error("expected run to report an error")
+		}
+	})
+
+	t.Run("failure of: go test -run", func(t *testing.T) {
+		cov := coverage.NewWithCmd(func(command string, args ...string) *exec.Cmd {
+			if strings.Contains(strings.Join(args, " "), "-list") {
+				return fakeExecCommandPerTest(command, args...)
+			}
+
+			return fakeExecCommandFailure(0)(command, args...)
+		}, t.TempDir(), mod)
+		if _, err := cov.RunPerTest(0); err == nil {
+			t.Error("expected run to report an error")
+		}
+	})
+}
+
+// TestCoveragePerTestProcess fakes go test: it lists the tests or writes a
+// different coverage profile for each package and test.
+func TestCoveragePerTestProcess(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+	args := os.Args
+	for i, a := range args {
+		if a == "--" {
+			args = args[i+1:]
+
+			break
+		}
+	}
+	var profile, run string
+	for i, a := range args {
+		switch a {
+		case "-list":
+			fmt.Print(testsList)
+			os.Exit(0) // skipcq: RVV-A0003
+		case "-coverprofile":
+			profile = args[i+1]
+		case "-run":
+			run = strings.Trim(args[i+1], "^$")
+		}
+	}
+	pkg := args[len(args)-1]
+	lines := map[string]string{
+		"example.com/path TestA":     "example.com/path/file1.go:10.2,11.16 2 1\nexample.com/path/file1.go:12.2,13.16 2 0\n",
+		"example.com/path ExampleB":  "example.com/path/file1.go:30.2,31.16 2 1\n",
+		"example.com/path/sub TestA": "example.com/path/file2.go:20.2,21.16 2 1\n",
+	}
+	data := "mode: set\n" + lines[pkg+" "+run]
+	if err := os.WriteFile(profile, []byte(data), 0600); err != nil {
+		os.Exit(1) // skipcq: RVV-A0003
+	}
+	os.Exit(0) // skipcq: RVV-A0003
+}
+
+func fakeExecCommandPerTest(command string, args ...string) *exec.Cmd {
+	cs := []string{"-test.run=TestCoveragePerTestProcess", "--", command}
+	cs = append(cs, args...)
+	// #nosec G204 - We are in tests, we don't care
+	cmd := exec.Command(os.Args[0], cs...)
+	cmd.Env = []string{"GO_TEST_PROCESS=1"}
+
+	return cmd
+}
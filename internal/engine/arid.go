@@ -0,0 +1,38 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"github.com/spf13/viper"
+
+	"github.com/singhnishant94/gremlins/internal/astutil"
+	"github.com/singhnishant94/gremlins/internal/configuration"
+)
+
+// ConfigureArid sets the rules of the arid calls, whose statements are not
+// mutated, from the configured presets, functions, packages and receivers.
+func ConfigureArid() error {
+	// NOTE: configuration.Get can't type cast to []string a value from
+	// .gremlins file, because viper.Get(k) returns []interface{}.
+	rules := astutil.AridRules{
+		Funcs:     viper.GetStringSlice(configuration.UnleashAridFunctionsKey),
+		Packages:  viper.GetStringSlice(configuration.UnleashAridPackagesKey),
+		Receivers: viper.GetStringSlice(configuration.UnleashAridReceiversKey),
+	}
+
+	return astutil.SetAridRules(viper.GetStringSlice(configuration.UnleashAridPresetsKey), rules)
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/astutil"
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const aridSource = `package arid
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+)
+
+var last bool
+
+type Span struct{}
+
+func (Span) End(ok bool) { last = ok }
+
+func (Span) Start(ok bool) (Span, bool) { return Span{}, ok }
+
+type Catalog struct{}
+
+func (Catalog) Error(ok bool) { last = ok }
+
+func Emit(ok bool) { last = ok }
+
+func Run(s Span, c Catalog, a, b int) {
+	Emit(a > b)
+	s.End(a > b)
+	flag.Bool("verbose", a > b, "")
+	slog.Default().Log(nil, slog.LevelInfo, "run", "ok", a > b)
+	_ = a > b
+	defer s.End(a > b)
+	t, ok := s.Start(a > b)
+	t.End(ok)
+	c.Error(a > b)
+	_ = fmt.Sprint(a > b)
+}
+`
+
+func TestAridRules(t *testing.T) {
+	testCases := []struct {
+		name  string
+		typed bool
+		set   map[string]any
+		want  []int
+	}{
+		{
+			name:  "typed without rules",
+			typed: true,
+			want:  []int{24, 25, 26, 27, 28, 29, 30, 32, 33},
+		},
+		{
+			name:  "typed function",
+			typed: true,
+			set:   map[string]any{configuration.UnleashAridFunctionsKey: []any{"Emit"}},
+			want:  []int{25, 26, 27, 28, 29, 30, 32, 33},
+		},
+		{
+			name:  "typed package",
+			typed: true,
+			set:   map[string]any{configuration.UnleashAridPackagesKey: []any{"flag"}},
+			want:  []int{24, 25, 27, 28, 29, 30, 32, 33},
+		},
+		{
+			name:  "typed receiver",
+			typed: true,
+			set:   map[string]any{configuration.UnleashAridReceiversKey: []any{"arid.Span"}},
+			want:  []int{24, 26, 27, 28, 32, 33},
+		},
+		{
+			name:  "typed receiver with import path",
+			typed: true,
+			set:   map[string]any{configuration.UnleashAridReceiversKey: []any{"example.com/arid.Span"}},
+			want:  []int{24, 26, 27, 28, 32, 33},
+		},
+		{
+			name:  "typed assignment",
+			typed: true,
+			set:   map[string]any{configuration.UnleashAridFunctionsKey: []any{"Start"}},
+			want:  []int{24, 25, 26, 27, 28, 29, 32, 33},
+		},
+		{
+			name:  "typed preset",
+			typed: true,
+			set:   map[string]any{configuration.UnleashAridPresetsKey: []any{"slog"}},
+			want:  []int{24, 25, 26, 28, 29, 30, 32, 33},
+		},
+		{
+			// The Error method of Catalog can't be told apart from the
+			// logging methods without the type information.
+			name: "untyped without rules",
+			want: []int{24, 25, 26, 27, 28, 29, 30, 33},
+		},
+		{
+			name: "untyped method",
+			set:  map[string]any{configuration.UnleashAridFunctionsKey: []any{"End"}},
+			want: []int{24, 26, 27, 28, 30, 33},
+		},
+		{
+			name: "untyped package",
+			set:  map[string]any{configuration.UnleashAridPackagesKey: []any{"flag"}},
+			want: []int{24, 25, 27, 28, 29, 30, 33},
+		},
+		{
+			name: "untyped preset",
+			set:  map[string]any{configuration.UnleashAridPresetsKey: []any{"slog"}},
+			want: []int{24, 25, 26, 28, 29, 30, 33},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mod := moduleWithSource(t, "arid", aridSource)
+			set := map[string]any{configuration.UnleashDryRunKey: true}
+			for k, v := range tc.set {
+				set[k] = v
+			}
+			viperSet(set)
+			defer viperReset()
+			if err := engine.ConfigureArid(); err != nil {
+				t.Fatal(err)
+			}
+			defer astutil.ResetAridRules()
+
+			var opts []engine.Option
+			if tc.typed {
+				pkgs, err := engine.LoadPackages(mod)
+				if err != nil {
+					t.Fatal(err)
+				}
+				opts = append(opts, engine.WithPackages(pkgs))
+			}
+			mut := engine.New(mod, engine.CodeData{}, newJobDealerStub(t), opts...)
+			res := mut.Run(context.Background())
+
+			lines := make(map[int]bool)
+			for _, m := range res.Mutants {
+				if m.Type() == mutator.ConditionalsBoundary {
+					lines[m.Position().Line] = true
+				}
+			}
+			var got []int
+			for l := range lines {
+				got = append(got, l)
+			}
+			sort.Ints(got)
+
+			if !cmp.Equal(got, tc.want) {
+				t.Error(cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestAridRulesUnknownPreset(t *testing.T) {
+	viperSet(map[string]any{configuration.UnleashAridPresetsKey: []any{"log4j"}})
+	defer viperReset()
+	defer astutil.ResetAridRules()
+
+	if err := engine.ConfigureArid(); err == nil {
+		t.Error("expected an error for the unknown preset")
+	}
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"bytes"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// Mutation changes the syntax tree of a file and returns the function which
+// puts it back as it was.
+type Mutation func() (rollback func())
+
+// ASTMutator is a mutator.Mutator which changes a node of the syntax tree
+// through a Mutation.
+//
+// As for the TokenMutator, the AST is shared among mutants, so the Mutation
+// is applied and rolled back holding the lock of the file, and only for the
+// time needed to write the mutated file.
+type ASTMutator struct {
+	pkg         string
+	fs          *token.FileSet
+	file        *ast.File
+	pos         token.Pos
+	end         token.Pos
+	replacement string
+	mutation    Mutation
+	workDir     string
+	rendered    *renderedSource
+	origFile    []byte
+	status      mutator.Status
+	mutantType  mutator.Type
+	diff        string
+	testExecErr error
+}
+
+// NewASTMutator initialises an ASTMutator which mutates the given node.
+// The replacement is the code put in place of the node, as shown in the
+// reports.
+func NewASTMutator(pkg string, set *token.FileSet, file *ast.File, node ast.Node, replacement ast.Node, mutation Mutation) *ASTMutator {
+	return &ASTMutator{
+		pkg:         pkg,
+		fs:          set,
+		file:        file,
+		pos:         node.Pos(),
+		end:         node.End(),
+		replacement: nodeString(replacement),
+		mutation:    mutation,
+	}
+}
+
+func nodeString(node ast.Node) string {
+	if node == nil {
+		return ""
+	}
+	w := &bytes.Buffer{}
+	if err := printer.Fprint(w, token.NewFileSet(), node); err != nil {
+		return ""
+	}
+
+	return w.String()
+}
+
+// Type returns the mutator.Type of the mutant.Mutator.
+func (m *ASTMutator) Type() mutator.Type {
+	return m.mutantType
+}
+
+// SetType sets the mutator.Type of the mutant.Mutator.
+func (m *ASTMutator) SetType(mt mutator.Type) {
+	m.mutantType = mt
+}
+
+// Status returns the mutator.Status of the mutant.Mutator.
+func (m *ASTMutator) Status() mutator.Status {
+	return m.status
+}
+
+// SetStatus sets the mutator.Status of the mutant.Mutator.
+func (m *ASTMutator) SetStatus(s mutator.Status) {
+	m.status = s
+}
+
+// Position returns the token.Position where the ASTMutator resides.
+func (m *ASTMutator) Position() token.Position {
+	return m.fs.Position(m.pos)
+}
+
+// Pos returns the token.Pos where the ASTMutator resides.
+func (m *ASTMutator) Pos() token.Pos {
+	return m.pos
+}
+
+// Diff returns the diff between the original and the mutation.
+func (m *ASTMutator) Diff() string {
+	return m.diff
+}
+
+// SetDiff sets the diff between the original and the mutation.
+func (m *ASTMutator) SetDiff(d string) {
+	m.diff = d
+}
+
+// Replacement returns the code which replaces the original one.
+func (m *ASTMutator) Replacement() string {
+	return m.replacement
+}
+
+// Location returns the start and end token.Position of the mutated node.
+func (m *ASTMutator) Location() (token.Position, token.Position) {
+	return m.Position(), m.fs.Position(m.end)
+}
+
+// Pkg returns the package name to which the mutant belongs.
+func (m *ASTMutator) Pkg() string {
+	return m.pkg
+}
+
+// Apply applies the Mutation, overwrites the source code file with the
+// mutated one and rolls back the Mutation. It stores the original file in
+// the ASTMutator in order to allow Rollback to put it back later.
+func (m *ASTMutator) Apply() error {
+	filename := filepath.Join(m.workDir, m.Position().Filename)
+	var err error
+	m.origFile, err = os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	return m.ApplyTo(filename)
+}
+
+// ApplyTo writes the mutated file to the given path and sets the diff, as
+// TokenMutator.ApplyTo does.
+func (m *ASTMutator) ApplyTo(filename string) error {
+	diff, err := applyMutant(filename, m.rendered, m.fs, m.file, m.mutation)
+	if err != nil {
+		return err
+	}
+	m.SetDiff(diff)
+
+	return nil
+}
+
+// prerender renders the mutated source ahead of the run, so that ApplyTo
+// doesn't need to lock the file.
+func (m *ASTMutator) prerender(rs renderedSources) {
+	m.rendered = rs.render(m.fs, m.file, m.mutation)
+}
+
+// Rollback puts back the original file after the test and cleans up the
+// ASTMutator to free memory.
+func (m *ASTMutator) Rollback() error {
+	defer m.resetOrigFile()
+	filename := filepath.Join(m.workDir, m.Position().Filename)
+
+	return os.WriteFile(filename, m.origFile, 0600)
+}
+
+func (m *ASTMutator) SetTestExecutionError(err error) {
+	m.testExecErr = err
+}
+
+func (m *ASTMutator) TestExecutionError() error {
+	return m.testExecErr
+}
+
+// SetWorkdir sets the base path on which to Apply and Rollback operations.
+//
+// By default, ASTMutator will operate on the same source on which the
+// analysis was performed. Changing the workdir will prevent the
+// modifications of the original files.
+func (m *ASTMutator) SetWorkdir(path string) {
+	m.workDir = path
+}
+
+// Workdir returns the current working dir in which the Mutator will apply its mutations.
+func (m *ASTMutator) Workdir() string {
+	return m.workDir
+}
+
+func (m *ASTMutator) resetOrigFile() {
+	var zeroByte []byte
+	m.origFile = zeroByte
+}
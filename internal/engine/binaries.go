@@ -0,0 +1,228 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// binaryTimeouts caches the time the test binaries of the packages without
+// mutations, built with go test -c, take to run. Each binary is built and
+// run once per package and build flags, then removed: the binaries of the
+// mutants can't be reused, as each of them has its own mutation, so only
+// the run time is kept to calibrate their timeouts.
+type binaryTimeouts struct {
+	mutex   sync.Mutex
+	entries map[string]*binaryTimeout
+}
+
+type binaryTimeout struct {
+	once    sync.Once
+	elapsed time.Duration
+	err     error
+}
+
+func newBinaryTimeouts() *binaryTimeouts {
+	return &binaryTimeouts{entries: make(map[string]*binaryTimeout)}
+}
+
+// baseline returns the run time of the test binary of the package built
+// with the flags, building and running it the first time.
+func (b *binaryTimeouts) baseline(m *mutantExecutor, pkg string, flags []string) (time.Duration, error) {
+	key := pkg + " " + strings.Join(flags, " ")
+	b.mutex.Lock()
+	bt, ok := b.entries[key]
+	if !ok {
+		bt = &binaryTimeout{}
+		b.entries[key] = bt
+	}
+	b.mutex.Unlock()
+
+	bt.once.Do(func() {
+		bt.elapsed, bt.err = m.runBaseline(pkg, flags)
+	})
+
+	return bt.elapsed, bt.err
+}
+
+// buildFlags returns the flags with which the tests of the mutant are built.
+func (m *mutantExecutor) buildFlags() []string {
+	var flags []string
+	if m.buildTags != "" {
+		flags = append(flags, "-tags", m.buildTags)
+	}
+	if raceTypes[m.mutant.Type()] {
+		flags = append(flags, "-race")
+	}
+
+	return flags
+}
+
+// runBaseline builds the test binary of the package from the source
+// without mutations, and measures the time it takes to run.
+func (m *mutantExecutor) runBaseline(pkg string, flags []string) (time.Duration, error) {
+	root := filepath.Join(m.module.Root, m.module.CallingDir)
+	dir, err := os.MkdirTemp(m.wdDealer.WorkDir(), "baseline-*")
+	if err != nil {
+		return 0, err
+	}
+	defer removeScratch(dir)
+
+	binary := filepath.Join(dir, "pkg.test")
+	if err := m.buildBinary(root, binary, pkg, flags, ""); err != nil {
+		return 0, err
+	}
+	// There is no binary if the package has no tests.
+	if _, err := os.Stat(binary); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.testExecutionTime)
+	defer cancel()
+	cmd := m.execContext(ctx, binary)
+	cmd.Dir = filepath.Join(root, filepath.Dir(m.mutant.Position().Filename))
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		return 0, fmt.Errorf("the tests of %s fail without mutations: %w", pkg, err)
+	}
+
+	return time.Since(start), nil
+}
+
+func (m *mutantExecutor) buildBinary(dir, binary, pkg string, flags []string, overlayFile string) error {
+	args := append([]string{"test", "-c", "-o", binary}, flags...)
+	if overlayFile != "" {
+		args = append(args, "-overlay", overlayFile)
+	}
+	args = append(args, pkg)
+	cmd := m.execContext(context.Background(), "go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("GOTMPDIR=%s", m.wdDealer.WorkDir()))
+
+	rel, err := run(cmd)
+	defer rel()
+
+	return err
+}
+
+// runTestBinary builds the test binary of the mutant with go test -c and
+// runs it, with a timeout measured on the run time of the binary of the
+// package without mutations. This way, the time spent compiling doesn't
+// count in the timeout, but each mutant still takes a build of its package.
+// It returns false if the binary of the package can't be built or run, or
+// if the result of the binary doesn't come from the tests.
+func (m *mutantExecutor) runTestBinary(pkg, overlayFile string) (mutator.Status, bool) {
+	flags := m.buildFlags()
+	elapsed, err := m.binaryTimeouts.baseline(m, pkg, flags)
+	if err != nil {
+		return mutator.Runnable, false
+	}
+	dir, err := os.MkdirTemp(m.wdDealer.WorkDir(), "mutant-*")
+	if err != nil {
+		return mutator.Runnable, false
+	}
+	defer removeScratch(dir)
+
+	binary := filepath.Join(dir, "pkg.test")
+	if err := m.buildBinary(m.mutant.Workdir(), binary, pkg, flags, overlayFile); err != nil {
+		m.mutant.SetTestExecutionError(err)
+
+		// The package builds without the mutation, so it is the mutation
+		// which doesn't build.
+		return mutator.NotViable, true
+	}
+	pkgDir := filepath.Join(m.mutant.Workdir(), filepath.Dir(m.mutant.Position().Filename))
+
+	return m.runBinary(binary, pkgDir, m.calibrate(elapsed))
+}
+
+// runBinary runs the test binary in the directory of its package, with the
+// additional environment variables. It returns false if the binary can't be
+// started or if its result doesn't come from the tests, so that they are
+// run by go test instead.
+func (m *mutantExecutor) runBinary(binary, dir string, timeout time.Duration, env ...string) (mutator.Status, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// As for go test, the timeout of the binary is longer than the one of
+	// gremlins.
+	args := []string{"-test.timeout", (2*time.Second + timeout).String(), "-test.failfast"}
+	if m.testCPU != 0 {
+		args = append(args, "-test.cpu", strconv.Itoa(m.testCPU))
+	}
+	if run := m.testsFilter(); run != "" {
+		args = append(args, "-test.run", run)
+	}
+	cmd := m.execContext(ctx, binary, args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd.Env = append(cmd.Env, env...)
+	out := &bytes.Buffer{}
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	rel, err := run(cmd)
+	defer rel()
+
+	m.mutant.SetTestExecutionError(err)
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return mutator.TimedOut, true
+	}
+	// The tests covering the mutant are not in the binary.
+	if bytes.Contains(out.Bytes(), []byte(noTestsWarning)) {
+		return mutator.NotCovered, true
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return getBinaryFailedStatus(exitErr.ExitCode(), out.Bytes())
+	}
+	if err != nil {
+		return mutator.Runnable, false
+	}
+
+	return mutator.Lived, true
+}
+
+// getBinaryFailedStatus returns the status of the mutant whose test binary
+// exits with the code. The binary is already built, so a failed test or a
+// panic kills the mutant. The other codes, like the one of a wrong flag,
+// don't come from the tests.
+func getBinaryFailedStatus(exitCode int, out []byte) (mutator.Status, bool) {
+	switch {
+	case exitCode == 1:
+		return mutator.Killed, true
+	case exitCode == 2 && (bytes.Contains(out, []byte("panic: ")) || bytes.Contains(out, []byte("fatal error: "))):
+		return mutator.Killed, true
+	default:
+		return mutator.Runnable, false
+	}
+}
@@ -0,0 +1,254 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/coverage"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/engine/workerpool"
+	"github.com/singhnishant94/gremlins/internal/gomodule"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const binariesSource = `package bin
+
+func Sum(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s += i
+	}
+
+	return s
+}
+
+func Greet(name string) string {
+	return "hello " + name
+}
+
+func At(s []int, i int) int {
+	return s[i-1]
+}
+`
+
+const binariesTestSource = `package bin
+
+import "testing"
+
+func TestSum(t *testing.T) {
+	if Sum(4) != 6 {
+		t.Fatal()
+	}
+}
+
+func TestGreet(t *testing.T) {
+	if Greet("x") != "hello x" {
+		t.Fatal()
+	}
+}
+
+func TestAt(t *testing.T) {
+	if At([]int{1, 2}, 1) != 1 {
+		t.Fatal()
+	}
+}
+`
+
+// binariesMutants returns the module of binariesSource and its RUNNABLE
+// mutants of the given types.
+func binariesMutants(t *testing.T, types map[mutator.Type]bool) (gomodule.GoModule, []mutator.Mutator) {
+	t.Helper()
+	mod := moduleWithSource(t, "bin", binariesSource)
+	if err := os.WriteFile(filepath.Join(mod.Root, "bin_test.go"), []byte(binariesTestSource), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The mutants are found without the type information, so that the
+	// ARITHMETIC_BASE on the string concatenation is not filtered out.
+	viperSet(map[string]any{configuration.UnleashDryRunKey: true})
+	defer viperReset()
+	mut := engine.New(mod, engine.CodeData{}, newJobDealerStub(t))
+	res := mut.Run(context.Background())
+	var mutants []mutator.Mutator
+	for _, m := range res.Mutants {
+		if types[m.Type()] {
+			m.SetStatus(mutator.Runnable)
+			mutants = append(mutants, m)
+		}
+	}
+
+	return mod, mutants
+}
+
+func TestBinaryTimeouts(t *testing.T) {
+	mod, mutants := binariesMutants(t, map[mutator.Type]bool{
+		mutator.ArithmeticBase:       true,
+		mutator.ConditionalsNegation: true,
+		mutator.IncrementDecrement:   true,
+	})
+
+	viperSet(map[string]any{configuration.UnleashBinaryTimeoutsKey: true})
+	defer viperReset()
+	// The mutated files are passed in an overlay, so the module is not
+	// copied.
+	wdDealer := newWdDealerStub(t)
+	wdDealer.fnGet = func(_ string) (string, error) {
+		t.Error("expected no copy of the module")
+
+		return t.TempDir(), nil
+	}
+	var binaries []string
+	var mu sync.Mutex
+	execContext := func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		if name == "go" {
+			if len(args) < 4 || args[1] != "-c" {
+				t.Errorf("expected the tests to be built with go test -c, got %q", args)
+			} else {
+				mu.Lock()
+				binaries = append(binaries, args[3])
+				mu.Unlock()
+			}
+		}
+
+		return exec.CommandContext(ctx, name, args...)
+	}
+	// The timeout of the coverage run is far longer than the one of the
+	// binaries, so the mutant looping forever is timed out on the latter.
+	dealer := engine.NewExecutorDealer(mod, wdDealer, expectedTimeout, engine.WithExecContext(execContext))
+
+	outCh := make(chan mutator.Mutator, len(mutants))
+	wg := &sync.WaitGroup{}
+	for _, m := range mutants {
+		wg.Add(1)
+		dealer.NewExecutor(m, outCh, wg).Start(&workerpool.Worker{Name: "test", ID: 1})
+	}
+	wg.Wait()
+	close(outCh)
+
+	var got []string
+	for m := range outCh {
+		got = append(got, fmt.Sprintf("%d %s %s", m.Position().Line, m.Type(), m.Status()))
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"13 ARITHMETIC_BASE NOT VIABLE",
+		"17 ARITHMETIC_BASE KILLED",
+		"5 CONDITIONALS_NEGATION KILLED",
+		"5 INCREMENT_DECREMENT TIMED OUT",
+	}
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+	// The binary of the package is built once without mutations, and once
+	// for each mutant. They are all removed once they have run.
+	if len(binaries) != len(mutants)+1 {
+		t.Errorf("expected %d builds, got %d", len(mutants)+1, len(binaries))
+	}
+	for _, b := range binaries {
+		if _, err := os.Stat(filepath.Dir(b)); !os.IsNotExist(err) {
+			t.Errorf("expected the folder of %s to be removed, got %v", b, err)
+		}
+	}
+}
+
+func TestBinaryTimeoutsWithoutResult(t *testing.T) {
+	testCases := []struct {
+		name        string
+		missingTest bool
+		noStart     bool
+		wantGoTest  bool
+		wantStatus  mutator.Status
+	}{
+		{
+			name:       "the binary is run",
+			wantStatus: mutator.Killed,
+		},
+		{
+			name:        "the covering tests are not in the binary",
+			missingTest: true,
+			wantStatus:  mutator.NotCovered,
+		},
+		{
+			name:       "the binary doesn't start",
+			noStart:    true,
+			wantGoTest: true,
+			wantStatus: mutator.Killed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mod, mutants := binariesMutants(t, map[mutator.Type]bool{mutator.ArithmeticBase: true})
+			var mut mutator.Mutator
+			for _, m := range mutants {
+				if m.Position().Line == 17 {
+					mut = m
+				}
+			}
+			if mut == nil {
+				t.Fatal("expected the mutant of At")
+			}
+
+			viperSet(map[string]any{configuration.UnleashBinaryTimeoutsKey: true})
+			defer viperReset()
+			goTest := false
+			execContext := func(ctx context.Context, name string, args ...string) *exec.Cmd {
+				switch {
+				case name == "go" && len(args) > 1 && args[1] != "-c":
+					goTest = true
+				case name != "go" && tc.noStart && len(args) > 0:
+					// Only the binary of the mutant is run with arguments.
+					name = filepath.Join(t.TempDir(), "missing.test")
+				}
+
+				return exec.CommandContext(ctx, name, args...)
+			}
+			opts := []engine.ExecutorDealerOption{engine.WithExecContext(execContext)}
+			if tc.missingTest {
+				pos := mut.Position()
+				opts = append(opts, engine.WithTestsProfile(coverage.TestsProfile{
+					{Package: mut.Pkg(), Name: "TestMissing"}: {pos.Filename: {{StartLine: pos.Line, StartCol: 1, EndLine: pos.Line, EndCol: 100}}},
+				}))
+			}
+			dealer := engine.NewExecutorDealer(mod, newWdDealerStub(t), expectedTimeout, opts...)
+
+			outCh := make(chan mutator.Mutator, 1)
+			wg := &sync.WaitGroup{}
+			wg.Add(1)
+			dealer.NewExecutor(mut, outCh, wg).Start(&workerpool.Worker{Name: "test", ID: 1})
+			wg.Wait()
+
+			if mut.Status() != tc.wantStatus {
+				t.Errorf("expected mutation to be %v, got %v", tc.wantStatus, mut.Status())
+			}
+			if goTest != tc.wantGoTest {
+				t.Errorf("expected go test to be run to be %v, got %v", tc.wantGoTest, goTest)
+			}
+		})
+	}
+}
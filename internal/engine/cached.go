@@ -0,0 +1,158 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/singhnishant94/gremlins/internal/cache"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// WithCache sets the cache.Cache used to reuse the verdicts of the
+// previous runs. The RUNNABLE mutants found in the cache are not executed,
+// and the verdicts of the executed ones are stored in it.
+func WithCache(c *cache.Cache) Option {
+	return func(m Engine) Engine {
+		m.cache = c
+
+		return m
+	}
+}
+
+// splitCached separates the mutants that need to be executed from the ones
+// whose verdict is in the cache. The latter get the cached status.
+func (mu *Engine) splitCached() ([]mutator.Mutator, []mutator.Mutator) {
+	if mu.cache == nil {
+		return mu.mutants, nil
+	}
+	mu.cacheKeys = make(map[mutator.Mutator]cache.Key)
+	hashes := make(map[string]string)
+
+	var toRun, cached []mutator.Mutator
+	for _, m := range mu.mutants {
+		if m.Status() != mutator.Runnable {
+			toRun = append(toRun, m)
+
+			continue
+		}
+		k, ok := mu.cacheKey(m, hashes)
+		if !ok {
+			toRun = append(toRun, m)
+
+			continue
+		}
+		mu.cacheKeys[m] = k
+		if s, ok := mu.cache.Get(k); ok {
+			m.SetStatus(s)
+			cached = append(cached, m)
+
+			continue
+		}
+		toRun = append(toRun, m)
+	}
+
+	return toRun, cached
+}
+
+// storeCached stores in the cache the verdicts of the executed mutants.
+func (mu *Engine) storeCached(mutants []mutator.Mutator) {
+	if mu.cache == nil {
+		return
+	}
+	for _, m := range mutants {
+		// The mutants which could not be applied are tried again in the
+		// next run.
+		if m.Status() == mutator.Skipped {
+			continue
+		}
+		if k, ok := mu.cacheKeys[m]; ok {
+			mu.cache.Set(k, m.Status())
+		}
+	}
+}
+
+func (mu *Engine) cacheKey(m mutator.Mutator, hashes map[string]string) (cache.Key, bool) {
+	pos := m.Position()
+	fileName := path.Clean(strings.ReplaceAll(pos.Filename, "\\", "/"))
+	fileHash, ok := memoHash(hashes, fileName, func() (string, bool) {
+		return mu.hashFiles([]string{fileName})
+	})
+	if !ok {
+		return cache.Key{}, false
+	}
+	dir := path.Dir(fileName)
+	testsHash, ok := memoHash(hashes, dir+"/*_test.go", func() (string, bool) {
+		tests, err := fs.Glob(mu.fs, path.Join(dir, "*_test.go"))
+		if err != nil {
+			return "", false
+		}
+
+		return mu.hashFiles(tests)
+	})
+	if !ok {
+		return cache.Key{}, false
+	}
+
+	k := cache.Key{
+		FileHash:  fileHash,
+		TestsHash: testsHash,
+		Type:      m.Type(),
+		Line:      pos.Line,
+		Column:    pos.Column,
+	}
+	if d, ok := m.(mutator.Describer); ok {
+		k.Replacement = d.Replacement()
+	}
+
+	return k, true
+}
+
+func memoHash(hashes map[string]string, key string, fn func() (string, bool)) (string, bool) {
+	if h, ok := hashes[key]; ok {
+		return h, true
+	}
+	h, ok := fn()
+	if ok {
+		hashes[key] = h
+	}
+
+	return h, ok
+}
+
+// hashFiles hashes the names and the contents of the files, sorted by name.
+func (mu *Engine) hashFiles(names []string) (string, bool) {
+	sort.Strings(names)
+	h := sha256.New()
+	for _, n := range names {
+		data, err := fs.ReadFile(mu.fs, n)
+		if err != nil {
+			return "", false
+		}
+		_, _ = h.Write([]byte(n))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write(data)
+		_, _ = h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), true
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/singhnishant94/gremlins/internal/cache"
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/engine/workerpool"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+func TestCachedResults(t *testing.T) {
+	testCases := []struct {
+		name       string
+		change     func(fs fstest.MapFS)
+		wantCached bool
+	}{
+		{
+			name:       "reuses the results if nothing changed",
+			change:     func(_ fstest.MapFS) {},
+			wantCached: true,
+		},
+		{
+			name: "runs again if the tests changed",
+			change: func(fs fstest.MapFS) {
+				fs["testdata/fixtures/gtr_test.go"] = &fstest.MapFile{Data: []byte("package main\n")}
+			},
+		},
+		{
+			name: "runs again if the source changed",
+			change: func(fs fstest.MapFS) {
+				fs["testdata/fixtures/gtr.go"].Data = append(fs["testdata/fixtures/gtr.go"].Data, '\n')
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mapFS, mod, c := loadFixture(defaultFixture, ".")
+			defer c()
+			// Only the mutants in the covered code of the fixture are
+			// cached, so the others are disabled.
+			viperSet(coveredMutantTypes())
+			defer viperReset()
+			resCache, err := cache.Load(filepath.Join(t.TempDir(), "cache"), "", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			first := &statusDealerStub{status: mutator.Killed}
+			mut := engine.New(mod, testCodeData, first, engine.WithDirFs(mapFS), engine.WithCache(resCache))
+			res := mut.Run(context.Background())
+			if len(first.got) == 0 || res.Cached != 0 {
+				t.Fatalf("expected the first run to execute all the mutants, cached %d", res.Cached)
+			}
+
+			tc.change(mapFS)
+			second := &statusDealerStub{status: mutator.Lived}
+			mut = engine.New(mod, testCodeData, second, engine.WithDirFs(mapFS), engine.WithCache(resCache))
+			res = mut.Run(context.Background())
+
+			if tc.wantCached {
+				if len(second.got) != 0 {
+					t.Errorf("expected no mutants to be executed, got %d", len(second.got))
+				}
+				if res.Cached != len(first.got) {
+					t.Errorf("expected %d cached results, got %d", len(first.got), res.Cached)
+				}
+				for _, m := range res.Mutants {
+					if m.Status() != mutator.Killed {
+						t.Errorf("expected cached status %s, got %s", mutator.Killed, m.Status())
+					}
+				}
+
+				return
+			}
+			if len(second.got) != len(first.got) || res.Cached != 0 {
+				t.Errorf("expected all the mutants to be executed again, got %d, cached %d", len(second.got), res.Cached)
+			}
+		})
+	}
+}
+
+func coveredMutantTypes() map[string]any {
+	enabled := map[mutator.Type]bool{
+		mutator.ConditionalsBoundary: true,
+		mutator.ConditionalsNegation: true,
+		mutator.RemoveStatement:      true,
+	}
+	set := make(map[string]any)
+	for _, mt := range mutator.Types {
+		set[configuration.MutantTypeEnabledKey(mt)] = enabled[mt]
+	}
+
+	return set
+}
+
+// statusDealerStub is an engine.ExecutorDealer whose executors set the
+// given status on the mutants.
+type statusDealerStub struct {
+	got    []mutator.Mutator
+	status mutator.Status
+}
+
+func (s *statusDealerStub) NewExecutor(mut mutator.Mutator, outCh chan<- mutator.Mutator, wg *sync.WaitGroup) workerpool.Executor {
+	s.got = append(s.got, mut)
+
+	return &statusExecutorStub{mut: mut, outCh: outCh, wg: wg, status: s.status}
+}
+
+type statusExecutorStub struct {
+	mut    mutator.Mutator
+	outCh  chan<- mutator.Mutator
+	wg     *sync.WaitGroup
+	status mutator.Status
+}
+
+func (e *statusExecutorStub) Start(_ *workerpool.Worker) {
+	e.mut.SetStatus(e.status)
+	e.outCh <- e.mut
+	e.wg.Done()
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"go/ast"
+	"go/types"
+
+	xastutil "golang.org/x/tools/go/ast/astutil"
+
+	"github.com/singhnishant94/gremlins/internal/astutil"
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// findCalls looks for the calls of the function body. The calls used as
+// statements are left to the RemoveStatement mutants, and the ones used as
+// a returned value to the ReturnZeroValue mutants, if enabled.
+func (r *typedFinder) findCalls(body *ast.BlockStmt, _ *types.Signature) {
+	skipReturns := configuration.Get[bool](configuration.MutantTypeEnabledKey(mutator.ReturnZeroValue))
+	ast.Inspect(body, func(node ast.Node) bool {
+		// Only the statements are checked, as the calls of functions
+		// declared in other files are arid.
+		if stmt, ok := node.(ast.Stmt); ok && detectAridNodes && astutil.IsAridNode(stmt, r.info) {
+			return false
+		}
+		switch n := node.(type) {
+		case *ast.ExprStmt, *ast.SelectorExpr:
+			// The methods can't be called on the zero values.
+		case *ast.ReturnStmt:
+			if !skipReturns {
+				r.findCallValues(n)
+			}
+		case *ast.CallExpr:
+			r.findCallValues(n)
+			r.findArgumentMutations(n)
+		default:
+			r.findCallValues(n)
+		}
+
+		return true
+	})
+}
+
+// findCallValues replaces the calls which are children of the node with
+// the zero value of their result type. The calls which are the condition of
+// an if or a for statement are left to the ConditionFalse mutants, if
+// enabled, as their zero value is false.
+func (r *typedFinder) findCallValues(node ast.Node) {
+	if node == nil {
+		return
+	}
+	skipConds := configuration.Get[bool](configuration.MutantTypeEnabledKey(mutator.ConditionFalse))
+	xastutil.Apply(node, func(c *xastutil.Cursor) bool {
+		if c.Node() == node {
+			return true
+		}
+		// The calls of the go and defer statements can't be replaced.
+		call, ok := c.Node().(*ast.CallExpr)
+		if !ok || c.Name() == "Call" || skipConds && c.Name() == "Cond" {
+			return false
+		}
+		if r.isFuncCall(call) && !r.removesLastUse(call) {
+			r.findCallValue(node, call)
+		}
+
+		return false
+	}, nil)
+}
+
+func (r *typedFinder) findCallValue(parent ast.Node, call *ast.CallExpr) {
+	t := r.info.TypeOf(call)
+	if _, isTuple := t.(*types.Tuple); isTuple || t == nil {
+		return
+	}
+	if zero, ok := r.typedZeroValue(t); ok {
+		r.add(mutator.CallZeroValue, call, zero, replaceChild(parent, call, zero))
+	}
+}
+
+// findArgumentMutations swaps the arguments of the same type and drops the
+// last variadic argument of the call.
+func (r *typedFinder) findArgumentMutations(call *ast.CallExpr) {
+	if !r.isFuncCall(call) {
+		return
+	}
+	sig, ok := r.info.TypeOf(call.Fun).Underlying().(*types.Signature)
+	if !ok {
+		return
+	}
+	args := call.Args
+	if call.Ellipsis.IsValid() {
+		args = args[:len(args)-1]
+	}
+	// A multi-value call used as arguments can't be swapped.
+	if !sig.Variadic() && len(call.Args) != sig.Params().Len() {
+		return
+	}
+
+	for i := range args {
+		for j := i + 1; j < len(args); j++ {
+			ti, oki := paramType(sig, i)
+			tj, okj := paramType(sig, j)
+			if !oki || !okj || !types.Identical(ti, tj) || nodeString(args[i]) == nodeString(args[j]) {
+				continue
+			}
+			swapped := &ast.CallExpr{Fun: call.Fun, Args: swapArgs(call.Args, i, j), Ellipsis: call.Ellipsis}
+			r.add(mutator.SwapArguments, call, swapped, replaceArgs(call, swapped.Args))
+		}
+	}
+
+	last := len(call.Args) - 1
+	if sig.Variadic() && !call.Ellipsis.IsValid() && last >= sig.Params().Len()-1 && !r.removesLastUse(call.Args[last]) {
+		dropped := &ast.CallExpr{Fun: call.Fun, Args: call.Args[:last:last]}
+		r.add(mutator.DropVariadic, call, dropped, replaceArgs(call, dropped.Args))
+	}
+}
+
+// isFuncCall checks if the call is a call of a function, and not a
+// conversion or a call of a builtin.
+func (r *typedFinder) isFuncCall(call *ast.CallExpr) bool {
+	tv, ok := r.info.Types[call.Fun]
+
+	return ok && !tv.IsType() && !tv.IsBuiltin()
+}
+
+// typedZeroValue returns the zero value of the type converted to the type
+// itself, so that the expression keeps its type also where the type is
+// inferred, as in the short variable declarations.
+func (r *typedFinder) typedZeroValue(t types.Type) (ast.Expr, bool) {
+	zero, ok := r.zeroValue(t)
+	if !ok {
+		return nil, false
+	}
+	switch zero.(type) {
+	case *ast.CompositeLit, *ast.StarExpr:
+		return zero, true
+	}
+	for _, def := range []types.Type{types.Typ[types.Bool], types.Typ[types.String], types.Typ[types.Int]} {
+		if types.Identical(t, def) {
+			return zero, true
+		}
+	}
+	typ, ok := r.typeExpr(t)
+	if !ok {
+		return nil, false
+	}
+	switch typ.(type) {
+	case *ast.StarExpr, *ast.FuncType, *ast.ChanType:
+		typ = &ast.ParenExpr{X: typ}
+	}
+
+	return &ast.CallExpr{Fun: typ, Args: []ast.Expr{zero}}, true
+}
+
+// paramType returns the type of the i-th argument of a call of the
+// function with the given signature.
+func paramType(sig *types.Signature, i int) (types.Type, bool) {
+	n := sig.Params().Len()
+	if !sig.Variadic() || i < n-1 {
+		return sig.Params().At(i).Type(), i < n
+	}
+	s, ok := sig.Params().At(n - 1).Type().Underlying().(*types.Slice)
+	if !ok {
+		return nil, false
+	}
+
+	return s.Elem(), true
+}
+
+func swapArgs(args []ast.Expr, i, j int) []ast.Expr {
+	swapped := make([]ast.Expr, len(args))
+	copy(swapped, args)
+	swapped[i], swapped[j] = swapped[j], swapped[i]
+
+	return swapped
+}
+
+// replaceArgs replaces the arguments of the call.
+func replaceArgs(call *ast.CallExpr, args []ast.Expr) Mutation {
+	return func() func() {
+		orig := call.Args
+		call.Args = args
+
+		return func() {
+			call.Args = orig
+		}
+	}
+}
+
+// replaceChild replaces the from node, which is a direct child of the
+// parent, with the to one.
+func replaceChild(parent, from, to ast.Node) Mutation {
+	return func() func() {
+		swapChild(parent, from, to)
+
+		return func() {
+			swapChild(parent, to, from)
+		}
+	}
+}
+
+func swapChild(parent, from, to ast.Node) {
+	xastutil.Apply(parent, func(c *xastutil.Cursor) bool {
+		if c.Node() == parent {
+			return true
+		}
+		if c.Node() == from {
+			c.Replace(to)
+		}
+
+		return false
+	}, nil)
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const callsSource = `package calls
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+func Delay() time.Duration {
+	return time.Second
+}
+
+func Join(sep string, parts ...string) string {
+	return strings.Join(parts, sep)
+}
+
+func Sub(a, b int) int {
+	return a - b
+}
+
+func Run(name string) (string, error) {
+	d := Delay()
+	msg := Join(",", name, "run")
+	n := Sub(len(msg), 1)
+	msg = Join(msg, d.String(), fmt.Sprint(n))
+	if strings.HasPrefix(msg, "x") {
+		return "", fmt.Errorf("bad %s", msg)
+	}
+
+	return msg, nil
+}
+`
+
+var callTypes = map[mutator.Type]bool{
+	mutator.CallZeroValue: true,
+	mutator.SwapArguments: true,
+	mutator.DropVariadic:  true,
+}
+
+func TestCallMutations(t *testing.T) {
+	mod := moduleWithSource(t, "calls", callsSource)
+	mutants := dryRun(t, mod, true, nil)
+
+	// At line 25, d and n are used for the last time, so the calls using them
+	// are not replaced. The call at line 26 is the condition of the if, so it
+	// is left to the CONDITION_FALSE mutant, and the call at line 27 is
+	// returned, so it is left to the RETURN_ZERO_VALUE mutant.
+	want := []string{
+		"22 CALL_ZERO_VALUE time.Duration(0)",
+		"23 CALL_ZERO_VALUE \"\"",
+		"23 DROP_VARIADIC Join(\",\", name)",
+		"23 SWAP_ARGUMENTS Join(\",\", \"run\", name)",
+		"23 SWAP_ARGUMENTS Join(\"run\", name, \",\")",
+		"23 SWAP_ARGUMENTS Join(name, \",\", \"run\")",
+		"24 CALL_ZERO_VALUE 0",
+		"24 SWAP_ARGUMENTS Sub(1, len(msg))",
+		"25 SWAP_ARGUMENTS Join(d.String(), msg, fmt.Sprint(n))",
+		"25 SWAP_ARGUMENTS Join(fmt.Sprint(n), d.String(), msg)",
+		"25 SWAP_ARGUMENTS Join(msg, fmt.Sprint(n), d.String())",
+		"26 SWAP_ARGUMENTS strings.HasPrefix(\"x\", msg)",
+		"27 DROP_VARIADIC fmt.Errorf(\"bad %s\")",
+	}
+	got := describeMutants(mutants, callTypes)
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestCallMutationsInConditions(t *testing.T) {
+	mod := moduleWithSource(t, "calls", callsSource)
+	mutants := dryRun(t, mod, true, map[string]any{
+		configuration.MutantTypeEnabledKey(mutator.ConditionFalse): false,
+	})
+
+	// Without the CONDITION_FALSE mutants, the condition of the if is
+	// replaced by the CALL_ZERO_VALUE one.
+	got := describeMutants(mutants, map[mutator.Type]bool{mutator.CallZeroValue: true})
+	for _, g := range got {
+		if g == "26 CALL_ZERO_VALUE false" {
+			return
+		}
+	}
+	t.Errorf("expected the condition to be replaced, got %v", got)
+}
+
+func TestCallMutationsWithoutTypes(t *testing.T) {
+	mod := moduleWithSource(t, "calls", callsSource)
+	mutants := dryRun(t, mod, false, nil)
+
+	if got := describeMutants(mutants, callTypes); len(got) != 0 {
+		t.Errorf("expected no call mutants without type information, got %v", got)
+	}
+}
+
+func TestCallMutationsCompile(t *testing.T) {
+	mod := moduleWithSource(t, "calls", callsSource)
+
+	if got := applyAndBuild(t, mod, callTypes); len(got) == 0 {
+		t.Error("expected call mutants to be applied")
+	}
+}
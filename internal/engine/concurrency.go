@@ -0,0 +1,221 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/singhnishant94/gremlins/internal/astutil"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// raceTypes are the mutator.Type of the concurrency mutants. Their tests are
+// run with the race detector, so that the data races they introduce kill
+// them.
+var raceTypes = map[mutator.Type]bool{
+	mutator.RemoveLock:      true,
+	mutator.SyncGoroutine:   true,
+	mutator.ChannelCapacity: true,
+	mutator.RemoveClose:     true,
+	mutator.RemoveWaitGroup: true,
+}
+
+// raceSlowdown is how many times the tests are expected to be slower when
+// run with the race detector.
+const raceSlowdown = 5
+
+// unlocks maps the methods locking a mutex to the ones unlocking it.
+var unlocks = map[string]string{
+	"(*sync.Mutex).Lock":    "(*sync.Mutex).Unlock",
+	"(*sync.RWMutex).Lock":  "(*sync.RWMutex).Unlock",
+	"(*sync.RWMutex).RLock": "(*sync.RWMutex).RUnlock",
+	"(sync.Locker).Lock":    "(sync.Locker).Unlock",
+}
+
+// findConcurrency looks for the statements of the function body which
+// synchronise the goroutines, and for the channels it creates.
+func (r *typedFinder) findConcurrency(body *ast.BlockStmt, _ *types.Signature) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		if stmt, ok := node.(ast.Stmt); ok && detectAridNodes && astutil.IsAridNode(stmt, r.info) {
+			return false
+		}
+		switch n := node.(type) {
+		case *ast.BlockStmt:
+			r.findConcurrentStmts(n.List)
+		case *ast.CaseClause:
+			r.findConcurrentStmts(n.Body)
+		case *ast.CommClause:
+			r.findConcurrentStmts(n.Body)
+		case *ast.CallExpr:
+			r.findChannelCapacity(n)
+		}
+
+		return true
+	})
+}
+
+func (r *typedFinder) findConcurrentStmts(list []ast.Stmt) {
+	for i, stmt := range list {
+		if g, ok := stmt.(*ast.GoStmt); ok {
+			sync := &ast.ExprStmt{X: g.Call}
+			r.add(mutator.SyncGoroutine, g, g.Call, replaceStmt(list, i, sync))
+
+			continue
+		}
+		call, ok := stmtCall(stmt)
+		if !ok {
+			continue
+		}
+		switch name := r.calleeName(call); name {
+		case "close":
+			r.removeStmts(mutator.RemoveClose, list, i)
+		case "(*sync.WaitGroup).Add", "(*sync.WaitGroup).Done":
+			r.removeStmts(mutator.RemoveWaitGroup, list, i)
+		default:
+			unlock, ok := unlocks[name]
+			if !ok {
+				continue
+			}
+			if j, found := r.findUnlock(list[i+1:], call, unlock); found {
+				r.removeStmts(mutator.RemoveLock, list, i, i+1+j)
+			}
+		}
+	}
+}
+
+// findUnlock finds the statement unlocking the mutex locked by the call.
+func (r *typedFinder) findUnlock(list []ast.Stmt, lock *ast.CallExpr, unlock string) (int, bool) {
+	mutex := nodeString(lock.Fun.(*ast.SelectorExpr).X)
+	for i, stmt := range list {
+		call, ok := stmtCall(stmt)
+		if !ok || r.calleeName(call) != unlock {
+			continue
+		}
+		if nodeString(call.Fun.(*ast.SelectorExpr).X) == mutex {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// removeStmts replaces the statements of the list at the given indexes with
+// a noop. The statements holding the only use of a variable are not removed.
+func (r *typedFinder) removeStmts(mt mutator.Type, list []ast.Stmt, idx ...int) {
+	var removed, kept []ast.Node
+	var mutations []Mutation
+	for _, i := range idx {
+		noop := astutil.CreateNoopOfStatement(list[i])
+		if d, ok := list[i].(*ast.DeferStmt); ok {
+			noop = astutil.CreateNoopOfStatement(&ast.ExprStmt{X: d.Call})
+		}
+		removed = append(removed, list[i])
+		kept = append(kept, noop)
+		mutations = append(mutations, replaceStmt(list, i, noop))
+	}
+	if r.replacesLastUses(removed, kept) {
+		return
+	}
+	r.add(mt, list[idx[0]], nil, combine(mutations...))
+}
+
+// findChannelCapacity makes the buffered channels unbuffered, and the
+// unbuffered ones buffered.
+func (r *typedFinder) findChannelCapacity(call *ast.CallExpr) {
+	if len(call.Args) == 0 || r.calleeName(call) != "make" {
+		return
+	}
+	if _, ok := r.info.TypeOf(call.Args[0]).Underlying().(*types.Chan); !ok {
+		return
+	}
+	var args []ast.Expr
+	switch {
+	case len(call.Args) == 1 || isConstantZero(r.info.Types[call.Args[1]].Value):
+		args = []ast.Expr{call.Args[0], &ast.BasicLit{Kind: token.INT, Value: "1"}}
+	case r.removesLastUse(call.Args[1]):
+		return
+	default:
+		args = []ast.Expr{call.Args[0]}
+	}
+	r.add(mutator.ChannelCapacity, call, &ast.CallExpr{Fun: call.Fun, Args: args}, replaceArgs(call, args))
+}
+
+// calleeName returns the full name of the function called, or the name of
+// the builtin.
+func (r *typedFinder) calleeName(call *ast.CallExpr) string {
+	switch fn := typeutil.Callee(r.info, call).(type) {
+	case *types.Func:
+		return fn.FullName()
+	case *types.Builtin:
+		return fn.Name()
+	default:
+		return ""
+	}
+}
+
+// stmtCall returns the call of the expression statements and of the defer
+// statements.
+func stmtCall(stmt ast.Stmt) (*ast.CallExpr, bool) {
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+
+		return call, ok
+	case *ast.DeferStmt:
+		return s.Call, true
+	default:
+		return nil, false
+	}
+}
+
+func isConstantZero(v constant.Value) bool {
+	return v != nil && v.Kind() == constant.Int && constant.Sign(v) == 0
+}
+
+// replaceStmt replaces the i-th statement of the list.
+func replaceStmt(list []ast.Stmt, i int, with ast.Stmt) Mutation {
+	return func() func() {
+		orig := list[i]
+		list[i] = with
+
+		return func() {
+			list[i] = orig
+		}
+	}
+}
+
+// combine applies the mutations together, and rolls them back in reverse
+// order.
+func combine(mutations ...Mutation) Mutation {
+	return func() func() {
+		rollbacks := make([]func(), len(mutations))
+		for i, m := range mutations {
+			rollbacks[i] = m()
+		}
+
+		return func() {
+			for i := len(rollbacks) - 1; i >= 0; i-- {
+				rollbacks[i]()
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const concurrencySource = `package conc
+
+import "sync"
+
+type Counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c *Counter) Inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+}
+
+func (c *Counter) Get() int {
+	c.mu.Lock()
+	n := c.n
+	c.mu.Unlock()
+
+	return n
+}
+
+func Sum(values []int) int {
+	var wg sync.WaitGroup
+	results := make(chan int, len(values))
+	for _, v := range values {
+		wg.Add(1)
+		go worker(&wg, results, v)
+	}
+	wg.Wait()
+	close(results)
+	total := 0
+	for r := range results {
+		total += r
+	}
+
+	return total
+}
+
+func worker(wg *sync.WaitGroup, results chan<- int, v int) {
+	defer wg.Done()
+	results <- v
+}
+
+func Signal() chan struct{} {
+	done := make(chan struct{}, 0)
+	go close(done)
+
+	return done
+}
+`
+
+var concurrencyTypes = map[mutator.Type]bool{
+	mutator.RemoveLock:      true,
+	mutator.SyncGoroutine:   true,
+	mutator.ChannelCapacity: true,
+	mutator.RemoveClose:     true,
+	mutator.RemoveWaitGroup: true,
+}
+
+func TestConcurrencyMutations(t *testing.T) {
+	mod := moduleWithSource(t, "conc", concurrencySource)
+	mutants := dryRun(t, mod, true, nil)
+
+	want := []string{
+		"11 REMOVE_LOCK ",
+		"17 REMOVE_LOCK ",
+		"26 CHANNEL_CAPACITY make(chan int)",
+		"28 REMOVE_WAITGROUP ",
+		"29 SYNC_GOROUTINE worker(&wg, results, v)",
+		"32 REMOVE_CLOSE ",
+		"42 REMOVE_WAITGROUP ",
+		"47 CHANNEL_CAPACITY make(chan struct{}, 1)",
+		"48 SYNC_GOROUTINE close(done)",
+	}
+	got := describeMutants(mutants, concurrencyTypes)
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestConcurrencyMutationsCompile(t *testing.T) {
+	mod := moduleWithSource(t, "conc", concurrencySource)
+
+	if got := applyAndBuild(t, mod, concurrencyTypes); len(got) == 0 {
+		t.Error("expected concurrency mutants to be applied")
+	}
+}
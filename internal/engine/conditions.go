@@ -0,0 +1,64 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// findConditionMutations replaces the whole condition with true and, if
+// withFalse is set, with false. The conditions which are already the
+// constant are not mutated, and neither are the ones containing the only use
+// of a variable when the type information is available. The conditions which
+// are a boolean literal are left to the BooleanLiteral mutants, if enabled.
+func (mu *Engine) findConditionMutations(pkg string, set *token.FileSet, file *ast.File, typed *typedFinder, cond *ast.Expr, withFalse bool) {
+	if typed.removesLastUse(*cond) {
+		return
+	}
+	if id, ok := (*cond).(*ast.Ident); ok && (id.Name == "true" || id.Name == "false") &&
+		configuration.Get[bool](configuration.MutantTypeEnabledKey(mutator.BooleanLiteral)) {
+		return
+	}
+	forced := []struct {
+		mt    mutator.Type
+		value string
+	}{
+		{mt: mutator.ConditionTrue, value: "true"},
+		{mt: mutator.ConditionFalse, value: "false"},
+	}
+	for _, f := range forced {
+		if f.mt == mutator.ConditionFalse && !withFalse {
+			continue
+		}
+		if !configuration.Get[bool](configuration.MutantTypeEnabledKey(f.mt)) {
+			continue
+		}
+		if id, ok := (*cond).(*ast.Ident); ok && id.Name == f.value {
+			continue
+		}
+		value := ast.NewIdent(f.value)
+		m := NewASTMutator(pkg, set, file, *cond, value, replaceExpr(cond, value))
+		m.SetType(f.mt)
+		m.SetStatus(mu.mutationStatus(set.Position((*cond).Pos())))
+
+		mu.mutants = append(mu.mutants, m)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const conditionsSource = `package conds
+
+import "os"
+
+func Count(n int) int {
+	c := 0
+	for c < n {
+		c++
+	}
+	for {
+		break
+	}
+	if true {
+		c--
+	}
+
+	return c
+}
+
+func Kind(n int) string {
+	switch {
+	case n < 0:
+		return "negative"
+	case n == 0, n == 1:
+		return "small"
+	}
+	switch n {
+	case 2:
+		return "two"
+	}
+
+	return "big"
+}
+
+func Exists(name string) bool {
+	if _, err := os.Stat(name); err != nil {
+		return false
+	}
+
+	return true
+}
+
+func Remove(name string) error {
+	if err := os.Remove(name); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func Check(ok bool) int {
+	if ok {
+		return 1
+	}
+
+	return 0
+}
+`
+
+var conditionTypes = map[mutator.Type]bool{
+	mutator.ConditionTrue:  true,
+	mutator.ConditionFalse: true,
+}
+
+func TestConditionMutations(t *testing.T) {
+	mod := moduleWithSource(t, "conds", conditionsSource)
+	mutants := dryRun(t, mod, true, map[string]any{
+		configuration.MutantTypeEnabledKey(mutator.BooleanLiteral): false,
+	})
+
+	// The condition at line 36 holds the only use of err, and the one at
+	// line 44 is dropped by the REMOVE_ERROR_CHECK mutant.
+	want := []string{
+		"13 CONDITION_FALSE false",
+		"22 CONDITION_FALSE false",
+		"22 CONDITION_TRUE true",
+		"24 CONDITION_FALSE false",
+		"24 CONDITION_FALSE false",
+		"24 CONDITION_TRUE true",
+		"24 CONDITION_TRUE true",
+		"44 CONDITION_TRUE true",
+		"52 CONDITION_FALSE false",
+		"52 CONDITION_TRUE true",
+		"7 CONDITION_FALSE false",
+		"7 CONDITION_TRUE true",
+	}
+	got := describeMutants(mutants, conditionTypes)
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestConditionMutationsWithoutTypes(t *testing.T) {
+	mod := moduleWithSource(t, "conds", conditionsSource)
+	mutants := dryRun(t, mod, false, map[string]any{
+		configuration.MutantTypeEnabledKey(mutator.BooleanLiteral): false,
+	})
+
+	// Without the type information, the error checks can't be told apart.
+	got := describeMutants(mutants, conditionTypes)
+	if len(got) != 15 {
+		t.Errorf("expected all the conditions to be mutated, got %v", got)
+	}
+}
+
+func TestConditionMutationsCompile(t *testing.T) {
+	mod := moduleWithSource(t, "conds", conditionsSource)
+
+	if got := applyAndBuild(t, mod, conditionTypes); len(got) == 0 {
+		t.Error("expected condition mutants to be applied")
+	}
+}
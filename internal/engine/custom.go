@@ -0,0 +1,93 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/singhnishant94/gremlins/internal/astutil"
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// CustomMutator finds the mutations of a project-specific mutator.Type.
+type CustomMutator interface {
+	// Name is the name of the mutator.Type, in upper snake case.
+	Name() string
+	// Mutations returns the mutations of the node. The info is nil if the
+	// type information of the package is not available.
+	Mutations(node ast.Node, info *types.Info) []CustomMutation
+}
+
+// CustomMutation is a mutation found by a CustomMutator.
+type CustomMutation struct {
+	// Node is the mutated node, which gives the position of the mutant.
+	Node ast.Node
+	// Replacement is the code put in place of the Node, as shown in the
+	// reports. It can be nil.
+	Replacement ast.Node
+	// Apply changes the syntax tree of the file.
+	Apply Mutation
+}
+
+type customMutator struct {
+	mt mutator.Type
+	m  CustomMutator
+}
+
+var customMutators []customMutator
+
+// RegisterCustom registers the CustomMutator with a new mutator.Type, which
+// gets a configuration key and a flag as the builtin ones, and returns it.
+//
+// RegisterCustom is meant to be called when the program is initialised,
+// before the flags are set. It panics if the name of the CustomMutator is
+// not valid or is already used.
+func RegisterCustom(m CustomMutator, enabled bool) mutator.Type {
+	mt := mutator.Register(m.Name())
+	configuration.SetDefaultEnabled(mt, enabled)
+	customMutators = append(customMutators, customMutator{mt: mt, m: m})
+
+	return mt
+}
+
+func (mu *Engine) findCustomMutations(pkg string, set *token.FileSet, file *ast.File, info *types.Info) {
+	for _, c := range customMutators {
+		if !configuration.Get[bool](configuration.MutantTypeEnabledKey(c.mt)) {
+			continue
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			if node == nil {
+				return false
+			}
+			if stmt, ok := node.(ast.Stmt); ok && detectAridNodes && astutil.IsAridNode(stmt, info) {
+				return false
+			}
+			for _, cm := range c.m.Mutations(node, info) {
+				m := NewASTMutator(pkg, set, file, cm.Node, cm.Replacement, cm.Apply)
+				m.SetType(c.mt)
+				m.SetStatus(mu.mutationStatus(set.Position(cm.Node.Pos())))
+
+				mu.mutants = append(mu.mutants, m)
+			}
+
+			return true
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// fixedTime replaces the calls to time.Now with time.Unix(0, 0).
+type fixedTime struct{}
+
+func (fixedTime) Name() string {
+	return "FIXED_TIME"
+}
+
+func (fixedTime) Mutations(node ast.Node, info *types.Info) []engine.CustomMutation {
+	call, ok := node.(*ast.CallExpr)
+	if !ok || info == nil {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Now" {
+		return nil
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "time" {
+		return nil
+	}
+	args := []ast.Expr{
+		&ast.BasicLit{Kind: token.INT, Value: "0"},
+		&ast.BasicLit{Kind: token.INT, Value: "0"},
+	}
+
+	return []engine.CustomMutation{{
+		Node:        call,
+		Replacement: &ast.CallExpr{Fun: &ast.SelectorExpr{X: sel.X, Sel: ast.NewIdent("Unix")}, Args: args},
+		Apply: func() func() {
+			sel.Sel.Name, call.Args = "Unix", args
+
+			return func() {
+				sel.Sel.Name, call.Args = "Now", nil
+			}
+		},
+	}}
+}
+
+var fixedTimeType = engine.RegisterCustom(fixedTime{}, false)
+
+const customSource = `package custom
+
+import "time"
+
+type Clock struct{ Now func() time.Time }
+
+func Elapsed(start time.Time) time.Duration {
+	return time.Now().Sub(start)
+}
+
+func NewClock() Clock {
+	return Clock{Now: time.Now}
+}
+
+func Later(c Clock) time.Time {
+	return c.Now().Add(time.Hour)
+}
+`
+
+var customTypes = map[mutator.Type]bool{fixedTimeType: true}
+
+func TestCustomMutations(t *testing.T) {
+	mod := moduleWithSource(t, "custom", customSource)
+	mutants := dryRun(t, mod, true, nil)
+
+	want := []string{"8 FIXED_TIME time.Unix(0, 0)"}
+	got := describeMutants(mutants, customTypes)
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestCustomMutationsDisabled(t *testing.T) {
+	mod := moduleWithSource(t, "custom", customSource)
+	mutants := dryRun(t, mod, true, map[string]any{
+		configuration.MutantTypeEnabledKey(fixedTimeType): false,
+	})
+
+	if got := describeMutants(mutants, customTypes); len(got) != 0 {
+		t.Errorf("expected no mutants of a disabled type, got %v", got)
+	}
+}
+
+func TestCustomMutationsCompile(t *testing.T) {
+	mod := moduleWithSource(t, "custom", customSource)
+
+	for _, m := range applyAndBuild(t, mod, customTypes) {
+		if m.Type().String() != "FIXED_TIME" {
+			t.Errorf("expected a FIXED_TIME mutant, got %s", m.Type())
+		}
+	}
+}
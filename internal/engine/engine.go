@@ -18,13 +18,12 @@ package engine
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"io/fs"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -32,10 +31,12 @@ import (
 	"time"
 
 	"github.com/singhnishant94/gremlins/internal/astutil"
+	"github.com/singhnishant94/gremlins/internal/cache"
 	"github.com/singhnishant94/gremlins/internal/coverage"
 	"github.com/singhnishant94/gremlins/internal/diff"
 	"github.com/singhnishant94/gremlins/internal/engine/workerpool"
 	"github.com/singhnishant94/gremlins/internal/exclusion"
+	"github.com/singhnishant94/gremlins/internal/log"
 	"github.com/singhnishant94/gremlins/internal/mutator"
 	"github.com/singhnishant94/gremlins/internal/report"
 
@@ -56,6 +57,14 @@ type Engine struct {
 	mutants  []mutator.Mutator
 	module   gomodule.GoModule
 	logger   report.MutantLogger
+	pkgs     *Packages
+	rules    []Rule
+
+	// generated are the generated files skipped.
+	generated []string
+
+	cache     *cache.Cache
+	cacheKeys map[mutator.Mutator]cache.Key
 }
 
 // CodeData is used to check if the mutant should be executed.
@@ -65,13 +74,6 @@ type CodeData struct {
 	Exclusion exclusion.Rules
 }
 
-type Comment struct {
-	Body string `json:"body"`
-	Path string `json:"path"`
-	Line int    `json:"line"`
-	Side string `json:"side"`
-}
-
 // Option for the Engine initialization.
 type Option func(m Engine) Engine
 
@@ -104,6 +106,17 @@ func WithDirFs(dirFS fs.FS) Option {
 	}
 }
 
+// WithPackages sets the type-checked Packages of the module. The files found
+// in the Packages are not parsed again, and their type information is used
+// to discard the mutations that would not compile.
+func WithPackages(pkgs *Packages) Option {
+	return func(m Engine) Engine {
+		m.pkgs = pkgs
+
+		return m
+	}
+}
+
 // Run executes the mutation testing.
 //
 // It walks the fs.FS provided and checks every .go file which is not a test.
@@ -124,6 +137,7 @@ func (mu *Engine) Run(ctx context.Context) report.Results {
 		return nil
 	})
 	// }()
+	mu.reportGenerated()
 	runnable := 0
 	for _, m := range mu.mutants {
 		if m.Status() == mutator.Runnable {
@@ -138,24 +152,30 @@ func (mu *Engine) Run(ctx context.Context) report.Results {
 	res := mu.executeTests(ctx)
 	res.Elapsed = time.Since(start)
 	res.Module = mu.module.Name
+	res.SrcDir = filepath.Join(mu.module.Root, mu.module.CallingDir)
 
 	return res
 }
 
 func (mu *Engine) runOnFile(fileName string) {
-	src, _ := mu.fs.Open(fileName)
-	set := token.NewFileSet()
-	file, err := parser.ParseFile(set, fileName, src, parser.ParseComments)
-	// file, _, , err := mu.parseAndTypeCheckFile(fileName)
+	set, file, info, err := mu.parseFile(fileName)
 	if err != nil {
-		_ = src.Close()
 		fmt.Printf("Error parsing file %s\n err: %s", fileName, err)
 		return
 	}
-	_ = src.Close()
+	if ast.IsGenerated(file) && !configuration.Get[bool](configuration.UnleashIncludeGeneratedKey) {
+		mu.generated = append(mu.generated, fileName)
+
+		return
+	}
+	if mu.codeData.Exclusion.IsPackageExcluded(mu.pkgName(fileName, file.Name.Name)) {
+		return
+	}
+	typed, hasTypes := mu.newTypedFinder(fileName, set, file, info)
+	found := len(mu.mutants)
 
 	ast.Inspect(file, func(node ast.Node) bool {
-		if detectAridNodes && astutil.IsAridNode(node) {
+		if detectAridNodes && astutil.IsAridNode(node, info) {
 			return false
 		}
 
@@ -164,13 +184,13 @@ func (mu *Engine) runOnFile(fileName string) {
 			return true
 		}
 
-		mu.findTokenMutations(fileName, set, file, n)
+		mu.findTokenMutations(fileName, set, file, info, n)
 
 		return true
 	})
 
 	ast.Inspect(file, func(node ast.Node) bool {
-		if detectAridNodes && astutil.IsAridNode(node) {
+		if detectAridNodes && astutil.IsAridNode(node, info) {
 			return false
 		}
 
@@ -179,13 +199,97 @@ func (mu *Engine) runOnFile(fileName string) {
 			return true
 		}
 
-		mu.findNodeMutations(fileName, set, file, n)
+		mu.findNodeMutations(fileName, set, file, info, typed, n)
 
 		return true
 	})
+
+	pkg := mu.pkgName(fileName, file.Name.Name)
+	mu.findLiteralMutations(pkg, set, file, info)
+	mu.findCustomMutations(pkg, set, file, info)
+	mu.findRuleMutations(pkg, set, file, info, typed)
+
+	if hasTypes {
+		typed.findMutations()
+	}
+
+	mu.mutants = append(mu.mutants[:found], mu.excludeFuncs(file, mu.mutants[found:])...)
+	ignoreSuppressed(set, file, mu.mutants[found:])
+}
+
+// excludeFuncs returns the mutants which are not in the functions excluded
+// by the exclusion.Rules.
+func (mu *Engine) excludeFuncs(file *ast.File, mutants []mutator.Mutator) []mutator.Mutator {
+	var excluded []*ast.FuncDecl
+	for _, decl := range file.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && mu.codeData.Exclusion.IsFuncExcluded(fd) {
+			excluded = append(excluded, fd)
+		}
+	}
+	if len(excluded) == 0 {
+		return mutants
+	}
+
+	kept := mutants[:0]
+	for _, m := range mutants {
+		if !inFuncs(m.Pos(), excluded) {
+			kept = append(kept, m)
+		}
+	}
+
+	return kept
+}
+
+func inFuncs(pos token.Pos, funcs []*ast.FuncDecl) bool {
+	for _, fd := range funcs {
+		if pos >= fd.Pos() && pos < fd.End() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reportGenerated reports how many generated files are skipped. In the
+// dry-run mode it lists them, so that the exclusions can be verified.
+func (mu *Engine) reportGenerated() {
+	if len(mu.generated) == 0 {
+		return
+	}
+	log.Infof("Skipped %d generated files\n", len(mu.generated))
+	if !configuration.Get[bool](configuration.UnleashDryRunKey) {
+		return
+	}
+	for _, f := range mu.generated {
+		log.Infof("  %s\n", f)
+	}
+}
+
+// parseFile returns the syntax tree of the file and, if the file is part of
+// the loaded Packages, its type information. Otherwise, the file is parsed
+// and the type information is nil.
+func (mu *Engine) parseFile(fileName string) (*token.FileSet, *ast.File, *types.Info, error) {
+	if file, info, ok := mu.pkgs.File(fileName); ok {
+		return mu.pkgs.FileSet(), file, info, nil
+	}
+
+	src, err := mu.fs.Open(fileName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	set := token.NewFileSet()
+	file, err := parser.ParseFile(set, fileName, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return set, file, nil, nil
 }
 
-func (mu *Engine) findTokenMutations(fileName string, set *token.FileSet, file *ast.File, node *NodeToken) {
+func (mu *Engine) findTokenMutations(fileName string, set *token.FileSet, file *ast.File, info *types.Info, node *NodeToken) {
 	mutantTypes, ok := TokenMutantType[node.Tok()]
 	if !ok {
 		return
@@ -196,6 +300,9 @@ func (mu *Engine) findTokenMutations(fileName string, set *token.FileSet, file *
 		if !configuration.Get[bool](configuration.MutantTypeEnabledKey(mt)) {
 			continue
 		}
+		if !isTypeCompatible(mt, node, info) {
+			continue
+		}
 		mutantType := mt
 		tm := NewTokenMutant(pkg, set, file, node)
 		tm.SetType(mutantType)
@@ -248,7 +355,44 @@ func (mu *Engine) mutationStatus(pos token.Position) mutator.Status {
 	return status
 }
 
-func (mu *Engine) findNodeMutations(fileName string, set *token.FileSet, file *ast.File, node *Node) {
+// findNodeMutations finds the mutations of the statements. The typedFinder
+// is nil if the type information is not available.
+func (mu *Engine) findNodeMutations(fileName string, set *token.FileSet, file *ast.File, info *types.Info, typed *typedFinder, node *Node) {
+	pkg := mu.pkgName(fileName, file.Name.Name)
+	switch n := (*node.node).(type) {
+	case *ast.IfStmt:
+		// The error checks are dropped by the RemoveErrorCheck mutants.
+		dropped := configuration.Get[bool](configuration.MutantTypeEnabledKey(mutator.RemoveErrorCheck)) && typed.isErrorCheck(n.Cond)
+		mu.findConditionMutations(pkg, set, file, typed, &n.Cond, !dropped)
+	case *ast.ForStmt:
+		if n.Cond != nil {
+			mu.findConditionMutations(pkg, set, file, typed, &n.Cond, true)
+		}
+	case *ast.SwitchStmt:
+		// Only the cases of the switch statements without a tag are
+		// boolean expressions.
+		if n.Tag != nil {
+			return
+		}
+		for _, stmt := range n.Body.List {
+			clause, ok := stmt.(*ast.CaseClause)
+			if !ok {
+				continue
+			}
+			for i := range clause.List {
+				mu.findConditionMutations(pkg, set, file, typed, &clause.List[i], true)
+			}
+		}
+	case *ast.BlockStmt, *ast.CaseClause:
+		mu.findStatementRemovals(pkg, set, file, info, node)
+	}
+}
+
+func (mu *Engine) findStatementRemovals(pkg string, set *token.FileSet, file *ast.File, info *types.Info, node *Node) {
+	if !configuration.Get[bool](configuration.MutantTypeEnabledKey(mutator.RemoveStatement)) {
+		return
+	}
+
 	// Statement block removal
 	var l []ast.Stmt
 
@@ -260,8 +404,8 @@ func (mu *Engine) findNodeMutations(fileName string, set *token.FileSet, file *a
 	}
 
 	for i, ni := range l {
-		if checkRemoveStatement(ni) {
-			tm := NewStmtRemover(mu.pkgName(fileName, file.Name.Name), set, file, node, i, ni.Pos())
+		if checkRemoveStatement(ni, info) {
+			tm := NewStmtRemover(pkg, set, file, node, i, ni.Pos())
 			tm.SetType(mutator.RemoveStatement)
 			tm.SetStatus(mu.mutationStatus(set.Position(tm.Pos())))
 
@@ -270,8 +414,8 @@ func (mu *Engine) findNodeMutations(fileName string, set *token.FileSet, file *a
 	}
 }
 
-func checkRemoveStatement(node ast.Stmt) bool {
-	if astutil.IsAridNode(node) {
+func checkRemoveStatement(node ast.Stmt, info *types.Info) bool {
+	if astutil.IsAridNode(node, info) {
 		return false
 	}
 
@@ -288,16 +432,27 @@ func checkRemoveStatement(node ast.Stmt) bool {
 }
 
 func (mu *Engine) executeTests(ctx context.Context) report.Results {
+	toRun, cached := mu.splitCached()
+
+	var mutants []mutator.Mutator
+	for _, m := range cached {
+		mu.logger.Mutant(m)
+		mutants = append(mutants, m)
+	}
+
+	if p, ok := mu.jDealer.(Preparer); ok {
+		p.Prepare(toRun)
+	}
+
 	pool := workerpool.Initialize("mutator")
 	pool.Start()
 
-	var mutants []mutator.Mutator
 	outCh := make(chan mutator.Mutator)
 	wg := &sync.WaitGroup{}
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		for _, mut := range mu.mutants {
+		for _, mut := range toRun {
 			ok := checkDone(ctx)
 			if !ok {
 				pool.Stop()
@@ -314,71 +469,17 @@ func (mu *Engine) executeTests(ctx context.Context) report.Results {
 		close(outCh)
 	}()
 
-	surfacedMutants := map[string]map[int]bool{}
-	comments := []Comment{}
-
+	var executed []mutator.Mutator
 	for m := range outCh {
 		mu.logger.Mutant(m)
-		mutants = append(mutants, m)
-
-		if m.Status() == mutator.Lived {
-			shouldComment := false
-
-			fileMutants, ok := surfacedMutants[m.Position().Filename]
-			if !ok {
-				fileMutants = map[int]bool{m.Position().Line: true}
-				surfacedMutants[m.Position().Filename] = fileMutants
-				shouldComment = true
-			} else {
-				_, ok := fileMutants[m.Position().Line]
-				if !ok {
-					fileMutants[m.Position().Line] = true
-					surfacedMutants[m.Position().Filename] = fileMutants
-					shouldComment = true
-				}
-			}
-
-			if shouldComment {
-				comment := Comment{
-					Body: getPRComment(m),
-					Path: m.Position().Filename,
-					Line: m.Position().Line,
-					Side: "RIGHT",
-				}
-				comments = append(comments, comment)
-			}
-		}
+		executed = append(executed, m)
 	}
+	mu.storeCached(executed)
 
-	// Marshal the data into JSON
-	jsonData, err := json.MarshalIndent(comments, "", "    ")
-	if err != nil {
-		log.Fatalf("Error occurred during marshalling. %v", err)
-	}
-
-	// Writing the JSON data to a file
-	fileName := "comments.json"
-	file, err := os.Create(fileName)
-	if err != nil {
-		log.Fatalf("Error occurred creating file: %v", err)
-	}
-	defer file.Close()
+	res := results(append(mutants, executed...))
+	res.Cached = len(cached)
 
-	// Write the JSON data to file
-	_, err = file.Write(jsonData)
-	if err != nil {
-		log.Fatalf("Error occurred writing to file: %v", err)
-	}
-	log.Printf("Data successfully written to %s", fileName)
-
-	return results(mutants)
-}
-
-func getPRComment(m mutator.Mutator) string {
-	return fmt.Sprintf(
-		"[gremlins] Changing the code like shown below does not cause any tests exercising them to fail.\n"+
-			"Consider adding tests that fail when the code is mutated.\n\n"+
-			"```diff\n%s\n```", m.Diff())
+	return res
 }
 
 func checkDone(ctx context.Context) bool {
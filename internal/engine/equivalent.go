@@ -0,0 +1,204 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// assemblies caches the assembly of the packages without mutations, printed
+// once per package by the compiler.
+type assemblies struct {
+	mutex   sync.Mutex
+	entries map[string]*assembly
+}
+
+type assembly struct {
+	once sync.Once
+	out  []byte
+	err  error
+}
+
+func newAssemblies() *assemblies {
+	return &assemblies{entries: make(map[string]*assembly)}
+}
+
+// original returns the assembly of the package of the mutant without
+// mutations, printing it the first time.
+func (a *assemblies) original(m *mutantExecutor) ([]byte, error) {
+	pkg := m.mutant.Pkg()
+	a.mutex.Lock()
+	asm, ok := a.entries[pkg]
+	if !ok {
+		asm = &assembly{}
+		a.entries[pkg] = asm
+	}
+	a.mutex.Unlock()
+
+	asm.once.Do(func() {
+		asm.out, asm.err = m.assembly("")
+	})
+
+	return asm.out, asm.err
+}
+
+// filterEquivalent marks the LIVED mutant as EQUIVALENT if it compiles to
+// the same code as the original. The overlay file of the mutant is written
+// if it is not given, which is the case when the mutation is applied to a
+// copy of the module, and must be called after the rollback.
+func (m *mutantExecutor) filterEquivalent(overlayFile string) {
+	if m.assemblies == nil || m.mutant.Status() != mutator.Lived {
+		return
+	}
+	if overlayFile == "" {
+		om, ok := m.mutant.(overlayMutator)
+		if !ok {
+			return
+		}
+		var err error
+		overlayFile, err = m.writeOverlay(om)
+		if err != nil {
+			return
+		}
+		defer removeScratch(filepath.Dir(overlayFile))
+	}
+	if m.isEquivalent(overlayFile) {
+		m.mutant.SetStatus(mutator.Equivalent)
+	}
+}
+
+// isEquivalent compares the assembly printed by the compiler for the code
+// of the declaration containing the mutant, with and without the mutation.
+// The functions in which this code is inlined are compared as well. The
+// mutated file is passed in the overlay file, so the positions in the two
+// outputs are the same. The mutant is not equivalent if any of the builds
+// fails, or if no code of the declaration is found.
+func (m *mutantExecutor) isEquivalent(overlayFile string) bool {
+	from, to, ok := m.declarationLines()
+	if !ok {
+		return false
+	}
+	original, err := m.assemblies.original(m)
+	if err != nil {
+		return false
+	}
+	mutated, err := m.assembly(overlayFile)
+	if err != nil {
+		return false
+	}
+	// With -trimpath, the files are named after the import path of their
+	// package.
+	file := m.mutant.Pkg() + "/" + filepath.Base(m.mutant.Position().Filename)
+	original = symbolsAt(original, file, from, to)
+	mutated = symbolsAt(mutated, file, from, to)
+
+	return len(original) != 0 && bytes.Equal(original, mutated)
+}
+
+// declarationLines returns the first and last lines of the top level
+// declaration containing the mutant.
+func (m *mutantExecutor) declarationLines() (int, int, bool) {
+	pos := m.mutant.Position()
+	set := token.NewFileSet()
+	file, err := parser.ParseFile(set, filepath.Join(m.mutant.Workdir(), pos.Filename), nil, parser.SkipObjectResolution)
+	if err != nil {
+		return 0, 0, false
+	}
+	for _, d := range file.Decls {
+		start, end := set.Position(d.Pos()), set.Position(d.End())
+		if start.Offset <= pos.Offset && pos.Offset < end.Offset {
+			return start.Line, end.Line, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+// assembly returns the assembly of the package of the mutant. The paths are
+// trimmed, so that the outputs of the copies of the module are the same.
+// As the other builds, it is not timed out.
+func (m *mutantExecutor) assembly(overlayFile string) ([]byte, error) {
+	args := []string{"build", "-trimpath", "-gcflags=-S", "-o", os.DevNull}
+	if m.buildTags != "" {
+		args = append(args, "-tags", m.buildTags)
+	}
+	if overlayFile != "" {
+		args = append(args, "-overlay", overlayFile)
+	}
+	args = append(args, m.mutant.Pkg())
+	cmd := m.execContext(context.Background(), "go", args...)
+	cmd.Dir = m.mutant.Workdir()
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("GOTMPDIR=%s", m.wdDealer.WorkDir()))
+
+	return cmd.CombinedOutput()
+}
+
+// instructionLine matches the position of an instruction in the output of
+// -gcflags=-S, like (example.com/pkg/file.go:12).
+var instructionLine = regexp.MustCompile(`^\s+0x[0-9a-f]+ \d+ \((.+):(\d+)\)`)
+
+// symbolsAt returns the assembly of the symbols having instructions on the
+// given lines of the file. The symbols start on a line without indentation,
+// and the lines of their code are indented. The other lines, like the name
+// of the package and the diagnostics of the compiler, are left out.
+func symbolsAt(out []byte, file string, from, to int) []byte {
+	var res, symbol bytes.Buffer
+	selected := false
+	flush := func() {
+		if selected {
+			res.Write(symbol.Bytes())
+		}
+		symbol.Reset()
+		selected = false
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 || (line[0] != ' ' && line[0] != '\t') {
+			flush()
+			if len(line) == 0 || line[0] == '#' {
+				continue
+			}
+		}
+		symbol.Write(line)
+		symbol.WriteByte('\n')
+		match := instructionLine.FindSubmatch(line)
+		if match == nil || string(match[1]) != file {
+			continue
+		}
+		if n, _ := strconv.Atoi(string(match[2])); n >= from && n <= to {
+			selected = true
+		}
+	}
+	flush()
+
+	return res.Bytes()
+}
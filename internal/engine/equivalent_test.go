@@ -0,0 +1,194 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/engine/workdir"
+	"github.com/singhnishant94/gremlins/internal/engine/workerpool"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const equivalentSource = `package equiv
+
+func Scale(n int) int {
+	return n * 1
+}
+
+func Abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func Rem(n, d int) int {
+	n %= d
+
+	return n
+}
+`
+
+const equivalentTestSource = `package equiv
+
+import "testing"
+
+func TestScale(t *testing.T) {
+	if Scale(3) != 3 {
+		t.Fatal()
+	}
+}
+
+func TestAbs(t *testing.T) {
+	if Abs(-2) != 2 || Abs(2) != 2 {
+		t.Fatal()
+	}
+}
+
+func TestRem(t *testing.T) {
+	if Rem(5, 5) != 0 {
+		t.Fatal()
+	}
+}
+`
+
+func TestEquivalentMutants(t *testing.T) {
+	testCases := []struct {
+		name       string
+		set        map[string]any
+		want       []string
+		wantBuilds int
+	}{
+		{
+			name: "marks the mutants compiling to the same code",
+			set:  map[string]any{configuration.UnleashEquivalentFilterKey: true},
+			want: []string{
+				"16 INVERT_ASSIGNMENTS EQUIVALENT",
+				"4 ARITHMETIC_BASE EQUIVALENT",
+				"8 CONDITIONALS_BOUNDARY LIVED",
+				"9 ARITHMETIC_BASE KILLED",
+			},
+			// The package is printed once without mutations, and once for
+			// each LIVED mutant.
+			wantBuilds: 4,
+		},
+		{
+			name: "marks them in overlay mode",
+			set: map[string]any{
+				configuration.UnleashEquivalentFilterKey: true,
+				configuration.UnleashOverlayKey:          true,
+			},
+			want: []string{
+				"16 INVERT_ASSIGNMENTS EQUIVALENT",
+				"4 ARITHMETIC_BASE EQUIVALENT",
+				"8 CONDITIONALS_BOUNDARY LIVED",
+				"9 ARITHMETIC_BASE KILLED",
+			},
+			wantBuilds: 4,
+		},
+		{
+			name: "can be turned off",
+			set:  map[string]any{configuration.UnleashEquivalentFilterKey: false},
+			want: []string{
+				"16 INVERT_ASSIGNMENTS LIVED",
+				"4 ARITHMETIC_BASE LIVED",
+				"8 CONDITIONALS_BOUNDARY LIVED",
+				"9 ARITHMETIC_BASE KILLED",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mod := moduleWithSource(t, "equiv", equivalentSource)
+			if err := os.WriteFile(filepath.Join(mod.Root, "equiv_test.go"), []byte(equivalentTestSource), 0600); err != nil {
+				t.Fatal(err)
+			}
+			types := map[mutator.Type]bool{
+				mutator.ArithmeticBase:       true,
+				mutator.ConditionalsBoundary: true,
+				mutator.InvertAssignments:    true,
+			}
+
+			viperSet(map[string]any{configuration.UnleashDryRunKey: true})
+			mut := engine.New(mod, engine.CodeData{}, newJobDealerStub(t))
+			res := mut.Run(context.Background())
+			viperReset()
+			var mutants []mutator.Mutator
+			for _, m := range res.Mutants {
+				if types[m.Type()] {
+					m.SetStatus(mutator.Runnable)
+					mutants = append(mutants, m)
+				}
+			}
+
+			viperSet(tc.set)
+			defer viperReset()
+			wdd := workdir.NewCachedDealer(t.TempDir(), mod.Root)
+			defer wdd.Clean()
+			wdDealer := newWdDealerStub(t)
+			wdDealer.fnGet = wdd.Get
+			builds := 0
+			var mu sync.Mutex
+			execContext := func(ctx context.Context, name string, args ...string) *exec.Cmd {
+				if name == "go" && len(args) > 0 && args[0] == "build" {
+					mu.Lock()
+					builds++
+					mu.Unlock()
+				}
+
+				return exec.CommandContext(ctx, name, args...)
+			}
+			dealer := engine.NewExecutorDealer(mod, wdDealer, expectedTimeout, engine.WithExecContext(execContext))
+
+			outCh := make(chan mutator.Mutator, len(mutants))
+			wg := &sync.WaitGroup{}
+			for _, m := range mutants {
+				wg.Add(1)
+				dealer.NewExecutor(m, outCh, wg).Start(&workerpool.Worker{Name: "test", ID: 1})
+			}
+			wg.Wait()
+			close(outCh)
+
+			var got []string
+			for m := range outCh {
+				got = append(got, fmt.Sprintf("%d %s %s", m.Position().Line, m.Type(), m.Status()))
+			}
+			sort.Strings(got)
+
+			if !cmp.Equal(got, tc.want) {
+				t.Error(cmp.Diff(tc.want, got))
+			}
+			if builds != tc.wantBuilds {
+				t.Errorf("expected %d builds, got %d", tc.wantBuilds, builds)
+			}
+		})
+	}
+}
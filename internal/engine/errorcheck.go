@@ -0,0 +1,187 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/singhnishant94/gremlins/internal/astutil"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+var errorInterface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// findErrorChecks looks for the `if err != nil` statements of the function
+// body, to drop them, to swallow the error they return and to unwrap the
+// error they wrap.
+func (r *typedFinder) findErrorChecks(body *ast.BlockStmt, _ *types.Signature) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		if detectAridNodes && astutil.IsAridNode(node, r.info) {
+			return false
+		}
+		stmt, ok := node.(*ast.IfStmt)
+		if !ok {
+			return true
+		}
+		err, ok := r.checkedError(stmt.Cond)
+		if !ok {
+			return true
+		}
+
+		if !r.removesLastUse(stmt.Cond) {
+			dropped := ast.NewIdent("false")
+			r.add(mutator.RemoveErrorCheck, stmt.Cond, dropped, replaceExpr(&stmt.Cond, dropped))
+		}
+		branchReturns(stmt.Body, func(ret *ast.ReturnStmt) {
+			for i, res := range ret.Results {
+				if r.sameVar(res, err) {
+					r.findSwallowError(ret, i)
+
+					continue
+				}
+				if wrapped, ok := r.wrappedError(res, err); ok {
+					r.findUnwrapError(ret, i, wrapped)
+				}
+			}
+		})
+
+		return true
+	})
+}
+
+// isErrorCheck checks if the condition compares an error to nil. Without
+// the type information it can't be known, so it returns false.
+func (r *typedFinder) isErrorCheck(cond ast.Expr) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.checkedError(cond)
+
+	return ok
+}
+
+// checkedError returns the error compared to nil by the condition.
+func (r *typedFinder) checkedError(cond ast.Expr) (ast.Expr, bool) {
+	be, ok := cond.(*ast.BinaryExpr)
+	if !ok || be.Op != token.NEQ {
+		return nil, false
+	}
+	err := be.X
+	switch {
+	case r.info.Types[be.Y].IsNil():
+	case r.info.Types[be.X].IsNil():
+		err = be.Y
+	default:
+		return nil, false
+	}
+	t := r.info.TypeOf(err)
+	if t == nil || r.info.Types[err].IsNil() || !types.Implements(t, errorInterface) {
+		return nil, false
+	}
+
+	return err, true
+}
+
+func (r *typedFinder) findSwallowError(ret *ast.ReturnStmt, i int) {
+	res := ret.Results[i]
+	if !isNilable(r.info.TypeOf(res)) || r.removesLastUse(res) {
+		return
+	}
+	nilErr := ast.NewIdent("nil")
+	if r.add(mutator.SwallowError, res, nilErr, replaceExpr(&ret.Results[i], nilErr)) {
+		r.swallowed[res] = true
+	}
+}
+
+func (r *typedFinder) findUnwrapError(ret *ast.ReturnStmt, i int, wrapped ast.Expr) {
+	if r.replacesLastUse(ret.Results[i], wrapped) {
+		return
+	}
+	r.add(mutator.UnwrapError, ret.Results[i], wrapped, replaceExpr(&ret.Results[i], wrapped))
+}
+
+// wrappedError checks if the expression is a call to fmt.Errorf wrapping
+// the error with the %w verb, and returns the wrapped error.
+func (r *typedFinder) wrappedError(expr, err ast.Expr) (ast.Expr, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) < 2 {
+		return nil, false
+	}
+	fn, ok := typeutil.Callee(r.info, call).(*types.Func)
+	if !ok || fn.FullName() != "fmt.Errorf" {
+		return nil, false
+	}
+	format := r.info.Types[call.Args[0]].Value
+	if format == nil || format.Kind() != constant.String || !strings.Contains(constant.StringVal(format), "%w") {
+		return nil, false
+	}
+	for _, arg := range call.Args[1:] {
+		if r.sameVar(arg, err) {
+			return arg, true
+		}
+	}
+
+	return nil, false
+}
+
+// sameVar checks if both the expressions are the same variable.
+func (r *typedFinder) sameVar(x, y ast.Expr) bool {
+	xi, ok := x.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	yi, ok := y.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	obj := r.info.Uses[xi]
+
+	return obj != nil && obj == r.info.Uses[yi]
+}
+
+// branchReturns calls fn for each return statement of the branch, skipping
+// the ones of the function literals.
+func branchReturns(body *ast.BlockStmt, fn func(ret *ast.ReturnStmt)) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			fn(n)
+		}
+
+		return true
+	})
+}
+
+func isNilable(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Interface, *types.Pointer, *types.Map, *types.Slice, *types.Chan, *types.Signature:
+		return true
+	default:
+		return false
+	}
+}
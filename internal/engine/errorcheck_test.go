@@ -0,0 +1,122 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const errorsSource = `package errs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrEmpty = errors.New("empty")
+
+func Read(name string) ([]byte, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	if len(data) == 0 {
+		return nil, ErrEmpty
+	}
+
+	return data, nil
+}
+
+func Open(name string) (*os.File, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func Close(f *os.File) error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func Sync(f *os.File) error {
+	if err := f.Sync(); err != nil {
+		return errors.New("sync failed")
+	}
+
+	return nil
+}
+
+func Stat(name string) error {
+	if _, err := os.Stat(name); err != nil {
+		return fmt.Errorf("stat %s: %v", name, err)
+	}
+
+	return nil
+}
+`
+
+var errorCheckTypes = map[mutator.Type]bool{
+	mutator.RemoveErrorCheck: true,
+	mutator.SwallowError:     true,
+	mutator.UnwrapError:      true,
+}
+
+func TestErrorCheckMutations(t *testing.T) {
+	mod := moduleWithSource(t, "errs", errorsSource)
+	mutants := dryRun(t, mod, true, nil)
+
+	// The check at line 41 is not removed, as the error is only used by the
+	// condition, and the error at line 50 is not wrapped with %w.
+	want := []string{
+		"13 REMOVE_ERROR_CHECK false",
+		"14 UNWRAP_ERROR err",
+		"25 REMOVE_ERROR_CHECK false",
+		"26 SWALLOW_ERROR nil",
+		"33 REMOVE_ERROR_CHECK false",
+		"34 SWALLOW_ERROR nil",
+		"49 REMOVE_ERROR_CHECK false",
+	}
+	got := describeMutants(mutants, errorCheckTypes)
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+
+	for _, m := range mutants {
+		if m.Type() == mutator.ReturnNilError && (m.Position().Line == 26 || m.Position().Line == 34) {
+			t.Errorf("expected the error at line %d to be swallowed only once", m.Position().Line)
+		}
+	}
+}
+
+func TestErrorCheckMutationsCompile(t *testing.T) {
+	mod := moduleWithSource(t, "errs", errorsSource)
+
+	if got := applyAndBuild(t, mod, errorCheckTypes); len(got) == 0 {
+		t.Error("expected error check mutants to be applied")
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/exclusion"
+)
+
+const exclusionSource = `package excluded
+
+type Level int
+
+func (l Level) String() string {
+	if l > 0 {
+		return "high"
+	}
+
+	return "low"
+}
+
+func Above(a, b int) bool {
+	return a > b
+}
+`
+
+func TestExcludedCode(t *testing.T) {
+	testCases := []struct {
+		name string
+		set  map[string]any
+		want []string
+	}{
+		{
+			name: "no exclusion",
+			set:  map[string]any{},
+			want: []string{"14 CONDITIONALS_BOUNDARY", "6 CONDITIONALS_BOUNDARY"},
+		},
+		{
+			name: "excluded method",
+			set:  map[string]any{configuration.UnleashExcludeFunctions: []any{"*.String"}},
+			want: []string{"14 CONDITIONALS_BOUNDARY"},
+		},
+		{
+			name: "excluded function",
+			set:  map[string]any{configuration.UnleashExcludeFunctions: []any{"Above"}},
+			want: []string{"6 CONDITIONALS_BOUNDARY"},
+		},
+		{
+			name: "excluded package",
+			set:  map[string]any{configuration.UnleashExcludePackages: []any{"example.com/..."}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mod := moduleWithSource(t, "excluded", exclusionSource)
+			set := map[string]any{configuration.UnleashDryRunKey: true}
+			for k, v := range tc.set {
+				set[k] = v
+			}
+			viperSet(set)
+			defer viperReset()
+
+			rules, err := exclusion.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			mut := engine.New(mod, engine.CodeData{Exclusion: rules}, newJobDealerStub(t))
+			res := mut.Run(context.Background())
+
+			var got []string
+			for _, m := range res.Mutants {
+				if m.Type().String() == "CONDITIONALS_BOUNDARY" {
+					got = append(got, fmt.Sprintf("%d %s", m.Position().Line, m.Type()))
+				}
+			}
+			sort.Strings(got)
+
+			if !cmp.Equal(got, tc.want) {
+				t.Error(cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
@@ -17,16 +17,21 @@
 package engine
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/coverage"
 	"github.com/singhnishant94/gremlins/internal/engine/workdir"
 	"github.com/singhnishant94/gremlins/internal/engine/workerpool"
 	"github.com/singhnishant94/gremlins/internal/gomodule"
@@ -38,28 +43,75 @@ import (
 // of each test run.
 const DefaultTimeoutCoefficient = 3
 
+// DefaultTimeoutFloor is the default shortest timeout of the tests of a
+// package, so that the very fast tests are not timed out by the noise of
+// starting a process.
+const DefaultTimeoutFloor = 5 * time.Second
+
+// testTimeoutPanic starts the message of the tests reaching the timeout of
+// go test.
+const testTimeoutPanic = "panic: test timed out after"
+
+// noTestsWarning is printed by the test binaries when the -test.run flag
+// matches no test.
+const noTestsWarning = "testing: warning: no tests to run"
+
 // ExecutorDealer is the initializer for new workerpool.Executor.
 type ExecutorDealer interface {
 	NewExecutor(mut mutator.Mutator, outCh chan<- mutator.Mutator, wg *sync.WaitGroup) workerpool.Executor
 }
 
+// Preparer is implemented by the ExecutorDealer which need to know all the
+// mutants to run before dealing their executors.
+type Preparer interface {
+	Prepare(mutants []mutator.Mutator)
+}
+
+// overlayMutator is implemented by the mutator.Mutator which can write the
+// mutated file elsewhere than over the source one, to pass it to the go
+// command with the -overlay flag.
+type overlayMutator interface {
+	ApplyTo(filename string) error
+}
+
+// prerenderer is implemented by the mutator.Mutator which can render their
+// mutated file before the mutants are executed, so that ApplyTo doesn't
+// need the file lock.
+type prerenderer interface {
+	prerender(rs renderedSources)
+}
+
 // MutantExecutorDealer is a ExecutorDealer for the initialisation of a mutantExecutor.
 //
 // By default, it sets uses exec.Command to perform the tests on the source
 // code. This can be overridden, for example in tests.
 //
+// In overlay mode, the tests are run on the source module, and the mutated
+// file is written in a scratch folder and passed to the go command with the
+// -overlay flag. This way, the workers need no copy of the module and there
+// is nothing to roll back. The mutated files are rendered in Prepare, before
+// the workers start, so they don't need the file locks either.
+//
 // The apply and rollback functions are wrappers around the TokenMutator apply and
 // rollback. These can be overridden with nop functions in tests. Not an
 // ideal setup. In the future we can think of a better way to handle this.
 type MutantExecutorDealer struct {
 	wdDealer          workdir.Dealer
 	execContext       execContext
+	testsProfile      coverage.TestsProfile
 	mod               gomodule.GoModule
 	buildTags         string
 	testExecutionTime time.Duration
+	pkgElapsed        map[string]time.Duration
+	timeoutFloor      time.Duration
+	timeoutCeiling    time.Duration
+	binaryTimeouts    *binaryTimeouts
 	dryRun            bool
 	integrationMode   bool
+	overlay           bool
+	assemblies        *assemblies
 	testCPU           int
+	coefficient       int
 }
 
 // ExecutorDealerOption is the defining option for the initialisation of a ExecutorDealer.
@@ -74,18 +126,47 @@ func WithExecContext(c execContext) ExecutorDealerOption {
 	}
 }
 
+// WithTestsProfile sets the per-test coverage, used to run only the tests
+// covering each mutant.
+func WithTestsProfile(tp coverage.TestsProfile) ExecutorDealerOption {
+	return func(m MutantExecutorDealer) MutantExecutorDealer {
+		m.testsProfile = tp
+
+		return m
+	}
+}
+
+// WithPackagesElapsed sets the time the tests of each package took in the
+// coverage run, used to calibrate the timeout of the mutants of each package.
+func WithPackagesElapsed(elapsed map[string]time.Duration) ExecutorDealerOption {
+	return func(m MutantExecutorDealer) MutantExecutorDealer {
+		m.pkgElapsed = elapsed
+
+		return m
+	}
+}
+
 // NewExecutorDealer initialises a MutantExecutorDealer.
 func NewExecutorDealer(mod gomodule.GoModule, wdd workdir.Dealer, elapsed time.Duration, opts ...ExecutorDealerOption) *MutantExecutorDealer {
 	buildTags := configuration.Get[string](configuration.UnleashTagsKey)
 	dryRun := configuration.Get[bool](configuration.UnleashDryRunKey)
 	integrationMode := configuration.Get[bool](configuration.UnleashIntegrationMode)
+	overlay := configuration.Get[bool](configuration.UnleashOverlayKey)
+	binaryTimeouts := configuration.Get[bool](configuration.UnleashBinaryTimeoutsKey)
+	equivalentFilter := configuration.Get[bool](configuration.UnleashEquivalentFilterKey)
 	testCPU := configuration.Get[int](configuration.UnleashTestCPUKey)
 	tCoefficient := configuration.Get[int](configuration.UnleashTimeoutCoefficientKey)
+	tFloor := configuration.Get[int](configuration.UnleashTimeoutFloorKey)
+	tCeiling := configuration.Get[int](configuration.UnleashTimeoutCeilingKey)
 
 	coefficient := DefaultTimeoutCoefficient
 	if tCoefficient != 0 {
 		coefficient = tCoefficient
 	}
+	floor := DefaultTimeoutFloor
+	if tFloor != 0 {
+		floor = time.Duration(tFloor) * time.Second
+	}
 
 	if testCPU != 0 && integrationMode {
 		testCPU /= testCPU
@@ -97,10 +178,25 @@ func NewExecutorDealer(mod gomodule.GoModule, wdd workdir.Dealer, elapsed time.D
 		buildTags:         buildTags,
 		dryRun:            dryRun,
 		integrationMode:   integrationMode,
+		overlay:           overlay,
 		testCPU:           testCPU,
 		testExecutionTime: elapsed * time.Duration(coefficient),
+		coefficient:       coefficient,
+		timeoutFloor:      floor,
+		timeoutCeiling:    time.Duration(tCeiling) * time.Second,
 		execContext:       exec.CommandContext,
 	}
+	if equivalentFilter {
+		jd.assemblies = newAssemblies()
+	}
+	// In integration mode, the tests of all the packages are run, so there
+	// is no single binary to build. The binary of each mutant is built from
+	// the module with the mutated file in an overlay, which spares the copy
+	// of the module and reuses the build cache of the other packages.
+	if binaryTimeouts && !integrationMode {
+		jd.binaryTimeouts = newBinaryTimeouts()
+		jd.overlay = true
+	}
 
 	for _, opt := range opts {
 		jd = opt(jd)
@@ -109,42 +205,79 @@ func NewExecutorDealer(mod gomodule.GoModule, wdd workdir.Dealer, elapsed time.D
 	return &jd
 }
 
+// Prepare renders in overlay mode the mutated files of the RUNNABLE mutants
+// covered by some test. It must be called before the executors start.
+func (m MutantExecutorDealer) Prepare(mutants []mutator.Mutator) {
+	if !m.overlay || m.dryRun {
+		return
+	}
+	rs := make(renderedSources)
+	for _, mut := range mutants {
+		p, ok := mut.(prerenderer)
+		if !ok || mut.Status() != mutator.Runnable {
+			continue
+		}
+		if tests, ok := coveringTests(m.testsProfile, mut, m.integrationMode); ok && len(tests) == 0 {
+			continue
+		}
+		p.prerender(rs)
+	}
+}
+
 // NewExecutor returns a new workerpool.Executor for the given mutator.Mutator.
 // It gets an output channel of mutator.Mutator and a sync.WaitGroup. The channel
 // will stream the results of the executor, and the wait group will be done when the
 // executor is complete.
 func (m MutantExecutorDealer) NewExecutor(mut mutator.Mutator, outCh chan<- mutator.Mutator, wg *sync.WaitGroup) workerpool.Executor {
-	mj := mutantExecutor{
-		mutant:            mut,
-		outCh:             outCh,
-		wg:                wg,
-		wdDealer:          m.wdDealer,
-		module:            m.mod,
-		dryRun:            m.dryRun,
-		integrationMode:   m.integrationMode,
-		buildTags:         m.buildTags,
-		execContext:       m.execContext,
-		testCPU:           m.testCPU,
-		testExecutionTime: m.testExecutionTime,
-	}
-
-	return &mj
+	return m.newMutantExecutor(mut, outCh, wg)
+}
+
+func (m MutantExecutorDealer) newMutantExecutor(mut mutator.Mutator, outCh chan<- mutator.Mutator, wg *sync.WaitGroup) *mutantExecutor {
+	return &mutantExecutor{
+		mutant:             mut,
+		outCh:              outCh,
+		wg:                 wg,
+		wdDealer:           m.wdDealer,
+		module:             m.mod,
+		dryRun:             m.dryRun,
+		integrationMode:    m.integrationMode,
+		overlay:            m.overlay,
+		assemblies:         m.assemblies,
+		buildTags:          m.buildTags,
+		execContext:        m.execContext,
+		testsProfile:       m.testsProfile,
+		testCPU:            m.testCPU,
+		testExecutionTime:  m.testExecutionTime,
+		pkgElapsed:         m.pkgElapsed,
+		timeoutFloor:       m.timeoutFloor,
+		timeoutCeiling:     m.timeoutCeiling,
+		binaryTimeouts:     m.binaryTimeouts,
+		timeoutCoefficient: m.coefficient,
+	}
 }
 
 type execContext = func(ctx context.Context, name string, args ...string) *exec.Cmd
 
 type mutantExecutor struct {
-	mutant            mutator.Mutator
-	wdDealer          workdir.Dealer
-	outCh             chan<- mutator.Mutator
-	wg                *sync.WaitGroup
-	execContext       execContext
-	module            gomodule.GoModule
-	buildTags         string
-	testExecutionTime time.Duration
-	dryRun            bool
-	integrationMode   bool
-	testCPU           int
+	mutant             mutator.Mutator
+	wdDealer           workdir.Dealer
+	outCh              chan<- mutator.Mutator
+	wg                 *sync.WaitGroup
+	execContext        execContext
+	testsProfile       coverage.TestsProfile
+	module             gomodule.GoModule
+	buildTags          string
+	testExecutionTime  time.Duration
+	pkgElapsed         map[string]time.Duration
+	timeoutFloor       time.Duration
+	timeoutCeiling     time.Duration
+	binaryTimeouts     *binaryTimeouts
+	dryRun             bool
+	integrationMode    bool
+	overlay            bool
+	assemblies         *assemblies
+	testCPU            int
+	timeoutCoefficient int
 }
 
 // Start is the implementation of the workerpool.Executor definition and is the
@@ -159,16 +292,44 @@ type mutantExecutor struct {
 // make it easy to distinguish failures from timeouts.
 func (m *mutantExecutor) Start(w *workerpool.Worker) {
 	defer m.wg.Done()
-	workerName := fmt.Sprintf("%s-%d", w.Name, w.ID)
-	rootDir, err := m.wdDealer.Get(workerName)
-	if err != nil {
-		panic("error, this is temporary")
+	om, overlay := m.mutant.(overlayMutator)
+	overlay = overlay && m.overlay
+	rootDir := m.module.Root
+	if !overlay {
+		workerName := fmt.Sprintf("%s-%d", w.Name, w.ID)
+		var err error
+		rootDir, err = m.wdDealer.Get(workerName)
+		if err != nil {
+			panic("error, this is temporary")
+		}
 	}
 
 	workingDir := filepath.Join(rootDir, m.module.CallingDir)
 	m.mutant.SetWorkdir(workingDir)
 
-	if m.mutant.Status() == mutator.NotCovered || m.mutant.Status() == mutator.Skipped || m.dryRun {
+	if tests, ok := coveringTests(m.testsProfile, m.mutant, m.integrationMode); ok && len(tests) == 0 && m.mutant.Status() == mutator.Runnable {
+		m.mutant.SetStatus(mutator.NotCovered)
+	}
+
+	if m.mutant.Status() == mutator.NotCovered || m.mutant.Status() == mutator.Skipped || m.mutant.Status() == mutator.Ignored || m.dryRun {
+		m.outCh <- m.mutant
+
+		return
+	}
+
+	if overlay {
+		overlayFile, err := m.writeOverlay(om)
+		if err != nil {
+			log.Errorf("failed to apply mutation at %s - %s\n\t%v\n", m.mutant.Position(), m.mutant.Status(), err)
+			m.mutant.SetStatus(mutator.Skipped)
+			m.outCh <- m.mutant
+
+			return
+		}
+		defer removeScratch(filepath.Dir(overlayFile))
+
+		m.mutant.SetStatus(m.test(rootDir, m.mutant.Pkg(), overlayFile))
+		m.filterEquivalent(overlayFile)
 		m.outCh <- m.mutant
 
 		return
@@ -176,31 +337,113 @@ func (m *mutantExecutor) Start(w *workerpool.Worker) {
 
 	if err := m.mutant.Apply(); err != nil {
 		log.Errorf("failed to apply mutation at %s - %s\n\t%v\n", m.mutant.Position(), m.mutant.Status(), err)
+		m.mutant.SetStatus(mutator.Skipped)
+		m.outCh <- m.mutant
 
 		return
 	}
 
-	m.mutant.SetStatus(m.runTests(rootDir, m.mutant.Pkg()))
+	m.mutant.SetStatus(m.test(rootDir, m.mutant.Pkg(), ""))
 
 	if err := m.mutant.Rollback(); err != nil {
 		// What should we do now?
 		log.Errorf("failed to restore mutation at %s - %s\n\t%v", m.mutant.Position(), m.mutant.Status(), err)
+		m.outCh <- m.mutant
+
+		return
 	}
+	m.filterEquivalent("")
 
 	m.outCh <- m.mutant
 }
 
-func (m *mutantExecutor) runTests(rootDir, pkg string) mutator.Status {
-	ctx, cancel := context.WithTimeout(context.Background(), m.testExecutionTime)
+// writeOverlay writes the mutated file, and the overlay file replacing the
+// source file with it, in a scratch folder of the working directory. It
+// returns the path of the overlay file.
+func (m *mutantExecutor) writeOverlay(om overlayMutator) (string, error) {
+	src, err := filepath.Abs(filepath.Join(m.mutant.Workdir(), m.mutant.Position().Filename))
+	if err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp(m.wdDealer.WorkDir(), "overlay-*")
+	if err != nil {
+		return "", err
+	}
+	overlayFile, err := writeOverlayFiles(om, src, dir)
+	if err != nil {
+		removeScratch(dir)
+
+		return "", err
+	}
+
+	return overlayFile, nil
+}
+
+func writeOverlayFiles(om overlayMutator, src, dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	mutated := filepath.Join(dir, filepath.Base(src))
+	if err := om.ApplyTo(mutated); err != nil {
+		return "", err
+	}
+
+	// This is the format expected by the -overlay flag of the go command.
+	overlay := struct {
+		Replace map[string]string
+	}{Replace: map[string]string{src: mutated}}
+	data, err := json.Marshal(overlay)
+	if err != nil {
+		return "", err
+	}
+	overlayFile := filepath.Join(dir, "overlay.json")
+
+	return overlayFile, os.WriteFile(overlayFile, data, 0600)
+}
+
+// removeScratch removes a scratch folder, like the one of the overlay file.
+func removeScratch(dir string) {
+	if err := os.RemoveAll(dir); err != nil {
+		log.Errorf("impossible to remove the temporary folder %s: %s\n", dir, err)
+	}
+}
+
+// test runs the tests of the mutant, through its test binary if the binary
+// timeouts are enabled.
+func (m *mutantExecutor) test(rootDir, pkg, overlayFile string) mutator.Status {
+	if m.binaryTimeouts != nil {
+		if status, ok := m.runTestBinary(pkg, overlayFile); ok {
+			return status
+		}
+	}
+
+	return m.runTests(rootDir, pkg, overlayFile)
+}
+
+func (m *mutantExecutor) runTests(rootDir, pkg, overlayFile string) mutator.Status {
+	// With a package timeout, the tests are timed out by go test, and the
+	// timeout of gremlins leaves them the time to build.
+	timeout := m.timeout()
+	pkgTimeout, calibrated := m.packageTimeout()
+	if calibrated {
+		timeout += pkgTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	cmd := m.execContext(ctx, "go", m.getTestArgs(pkg)...)
+	cmd := m.execContext(ctx, "go", m.getTestArgs(pkg, overlayFile)...)
 	cmd.Dir = m.mutant.Workdir()
 	if m.integrationMode {
 		cmd.Dir = rootDir
 	}
 	cmd.Env = append(cmd.Env, os.Environ()...)
 	cmd.Env = append(cmd.Env, fmt.Sprintf("GOTMPDIR=%s", m.wdDealer.WorkDir()))
+	out := &bytes.Buffer{}
+	if calibrated {
+		cmd.Stdout = out
+		cmd.Stderr = out
+	}
 
 	rel, err := run(cmd)
 	defer rel()
@@ -210,6 +453,9 @@ func (m *mutantExecutor) runTests(rootDir, pkg string) mutator.Status {
 	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 		return mutator.TimedOut
 	}
+	if calibrated && bytes.Contains(out.Bytes(), []byte(testTimeoutPanic)) {
+		return mutator.TimedOut
+	}
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) {
 		return getTestFailedStatus(exitErr.ExitCode())
@@ -218,20 +464,33 @@ func (m *mutantExecutor) runTests(rootDir, pkg string) mutator.Status {
 	return mutator.Lived
 }
 
-func (m *mutantExecutor) getTestArgs(pkg string) []string {
+func (m *mutantExecutor) getTestArgs(pkg, overlayFile string) []string {
 	args := []string{"test"}
 	if m.buildTags != "" {
 		args = append(args, "-tags", m.buildTags)
 	}
+	if overlayFile != "" {
+		args = append(args, "-overlay", overlayFile)
+	}
 	// Here we add some seconds to the timeout to be sure it's gremlins that catches the test
 	// timeout and not the test itself. The timeout on the test prevents the test.* processes
-	// from hanging forever.
-	args = append(args, "-timeout", (2*time.Second + m.testExecutionTime).String())
+	// from hanging forever. With a package timeout, it's the test which catches it instead.
+	timeout := 2*time.Second + m.timeout()
+	if pkgTimeout, ok := m.packageTimeout(); ok {
+		timeout = pkgTimeout
+	}
+	args = append(args, "-timeout", timeout.String())
 	args = append(args, "-failfast")
+	if raceTypes[m.mutant.Type()] {
+		args = append(args, "-race")
+	}
 
 	if m.testCPU != 0 {
 		args = append(args, fmt.Sprintf("-cpu %d", m.testCPU))
 	}
+	if run := m.testsFilter(); run != "" {
+		args = append(args, "-run", run)
+	}
 
 	path := pkg
 	if m.integrationMode {
@@ -242,6 +501,84 @@ func (m *mutantExecutor) getTestArgs(pkg string) []string {
 	return args
 }
 
+// timeout returns the time given to the tests, which is longer for the
+// mutants run with the race detector.
+func (m *mutantExecutor) timeout() time.Duration {
+	if raceTypes[m.mutant.Type()] {
+		return m.testExecutionTime * raceSlowdown
+	}
+
+	return m.testExecutionTime
+}
+
+// packageTimeout returns the time given to the tests of the package of the
+// mutant, calibrated on the time they took in the coverage run, without the
+// time to build them. It returns false if they were not timed, and in
+// integration mode, where all the packages are tested.
+func (m *mutantExecutor) packageTimeout() (time.Duration, bool) {
+	if m.integrationMode {
+		return 0, false
+	}
+	elapsed, ok := m.pkgElapsed[m.mutant.Pkg()]
+	if !ok {
+		return 0, false
+	}
+	if raceTypes[m.mutant.Type()] {
+		elapsed *= raceSlowdown
+	}
+
+	return m.calibrate(elapsed), true
+}
+
+// calibrate returns the timeout of tests which take elapsed to run without
+// mutations: elapsed multiplied by the timeout coefficient, within the
+// timeout floor and ceiling.
+func (m *mutantExecutor) calibrate(elapsed time.Duration) time.Duration {
+	timeout := elapsed * time.Duration(m.timeoutCoefficient)
+	if timeout < m.timeoutFloor {
+		timeout = m.timeoutFloor
+	}
+	if m.timeoutCeiling != 0 && timeout > m.timeoutCeiling {
+		timeout = m.timeoutCeiling
+	}
+
+	return timeout
+}
+
+// testsFilter returns the -run pattern matching only the tests covering the
+// mutant. If the per-test coverage is not available, it returns an empty
+// string and all the tests are run.
+func (m *mutantExecutor) testsFilter() string {
+	tests, ok := coveringTests(m.testsProfile, m.mutant, m.integrationMode)
+	if !ok || len(tests) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("^(%s)$", strings.Join(tests, "|"))
+}
+
+// coveringTests returns the sorted names of the tests covering the mutant,
+// among the ones of its package or, in integration mode, of all the
+// packages. It returns false if the per-test coverage is not available.
+func coveringTests(tp coverage.TestsProfile, mut mutator.Mutator, integrationMode bool) ([]string, bool) {
+	if tp == nil {
+		return nil, false
+	}
+	names := make(map[string]bool)
+	for _, t := range tp.Tests(mut.Position()) {
+		if integrationMode || t.Package == mut.Pkg() {
+			names[t.Name] = true
+		}
+	}
+	tests := make([]string, 0, len(names))
+	for n := range names {
+		tests = append(tests, n)
+	}
+	sort.Strings(tests)
+
+	return tests, true
+}
+
 func run(cmd *exec.Cmd) (func(), error) {
 	if err := cmd.Run(); err != nil {
 
@@ -18,9 +18,12 @@ package engine_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"go/token"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -29,6 +32,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/coverage"
 	"github.com/singhnishant94/gremlins/internal/engine"
 	"github.com/singhnishant94/gremlins/internal/engine/workerpool"
 	"github.com/singhnishant94/gremlins/internal/gomodule"
@@ -76,7 +80,7 @@ func TestApplyAndRollback(t *testing.T) {
 		}
 	})
 
-	t.Run("does nothing if apply goes to error", func(t *testing.T) {
+	t.Run("skips the mutant if apply goes to error", func(t *testing.T) {
 		wdDealer := newWdDealerStub(t)
 		tmpDir, _ := wdDealer.Get("")
 		mod := gomodule.GoModule{
@@ -115,6 +119,45 @@ func TestApplyAndRollback(t *testing.T) {
 		if mut.rollbackCalled {
 			t.Errorf("expected rollback not to be called")
 		}
+		if mut.Status() != mutator.Skipped {
+			t.Errorf("expected mutation to be %v, got %v", mutator.Skipped, mut.Status())
+		}
+	})
+
+	t.Run("skips the mutant if the overlay can't be written", func(t *testing.T) {
+		viperSet(map[string]any{configuration.UnleashOverlayKey: true})
+		defer viperReset()
+
+		wdDealer := newWdDealerStub(t)
+		mod := gomodule.GoModule{
+			Name:       "example.com",
+			Root:       t.TempDir(),
+			CallingDir: ".",
+		}
+		mjd := engine.NewExecutorDealer(mod, wdDealer, expectedTimeout, engine.WithExecContext(fakeExecCommandSuccess))
+		mut := &overlayMutantStub{mutantStub: mutantStub{
+			status:        mutator.Runnable,
+			mutType:       mutator.ConditionalsBoundary,
+			pkg:           "example.com",
+			position:      token.Position{Filename: "file.go"},
+			hasApplyError: true,
+		}}
+		outCh := make(chan mutator.Mutator)
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		executor := mjd.NewExecutor(mut, outCh, &wg)
+		go func() {
+			<-outCh
+			close(outCh)
+		}()
+
+		executor.Start(&workerpool.Worker{Name: "test", ID: 1})
+
+		wg.Wait()
+
+		if mut.Status() != mutator.Skipped {
+			t.Errorf("expected mutation to be %v, got %v", mutator.Skipped, mut.Status())
+		}
 	})
 }
 
@@ -324,6 +367,215 @@ func TestMutatorRun(t *testing.T) {
 	}
 }
 
+func TestMutatorRunsOnlyCoveringTests(t *testing.T) {
+	testsProfile := coverage.TestsProfile{
+		{Package: "example.com", Name: "TestA"}:       {"file.go": {{StartLine: 9, StartCol: 1, EndLine: 11, EndCol: 1}}},
+		{Package: "example.com", Name: "TestB"}:       {"file.go": {{StartLine: 10, StartCol: 1, EndLine: 10, EndCol: 20}}},
+		{Package: "example.com", Name: "TestC"}:       {"file.go": {{StartLine: 20, StartCol: 1, EndLine: 30, EndCol: 1}}},
+		{Package: "example.com/other", Name: "TestD"}: {"file.go": {{StartLine: 10, StartCol: 1, EndLine: 40, EndCol: 20}}},
+	}
+	testCases := []struct {
+		name       string
+		position   token.Position
+		intMode    bool
+		wantRun    string
+		wantStatus mutator.Status
+	}{
+		{
+			name:       "runs only the tests of the package covering the mutant",
+			position:   token.Position{Filename: "file.go", Line: 10, Column: 3},
+			wantRun:    "-run ^(TestA|TestB)$ example.com",
+			wantStatus: mutator.Lived,
+		},
+		{
+			name:       "runs the tests of all the packages in integration mode",
+			position:   token.Position{Filename: "file.go", Line: 10, Column: 3},
+			intMode:    true,
+			wantRun:    "-run ^(TestA|TestB|TestD)$ ./...",
+			wantStatus: mutator.Lived,
+		},
+		{
+			name:       "is NOT COVERED if no test of the package covers the mutant",
+			position:   token.Position{Filename: "file.go", Line: 40, Column: 3},
+			wantStatus: mutator.NotCovered,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			viperSet(map[string]any{configuration.UnleashIntegrationMode: tc.intMode})
+			defer viperReset()
+
+			mod := gomodule.GoModule{
+				Name:       "example.com",
+				Root:       ".",
+				CallingDir: ".",
+			}
+			holder := &commandHolder{}
+			mjd := engine.NewExecutorDealer(mod, newWdDealerStub(t), expectedTimeout,
+				engine.WithExecContext(fakeExecCommandSuccessWithHolder(holder)),
+				engine.WithTestsProfile(testsProfile))
+			mut := &mutantStub{
+				status:   mutator.Runnable,
+				mutType:  mutator.ConditionalsBoundary,
+				pkg:      "example.com",
+				position: tc.position,
+			}
+			outCh := make(chan mutator.Mutator)
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+			executor := mjd.NewExecutor(mut, outCh, &wg)
+			go func() {
+				<-outCh
+				close(outCh)
+			}()
+			executor.Start(&workerpool.Worker{Name: "test", ID: 1})
+			wg.Wait()
+
+			if mut.Status() != tc.wantStatus {
+				t.Errorf("expected status %s, got %s", tc.wantStatus, mut.Status())
+			}
+			args := strings.Join(holder.args, " ")
+			if tc.wantRun == "" {
+				if args != "" {
+					t.Errorf("expected no test run, got %q", args)
+				}
+
+				return
+			}
+			if !strings.Contains(args, tc.wantRun) {
+				t.Errorf("expected %q in %q", tc.wantRun, args)
+			}
+		})
+	}
+}
+
+func TestMutatorRunsConcurrencyMutantsWithRace(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mutType  mutator.Type
+		wantRace bool
+	}{
+		{
+			name:     "runs the concurrency mutants with the race detector",
+			mutType:  mutator.RemoveLock,
+			wantRace: true,
+		},
+		{
+			name:    "runs the other mutants without the race detector",
+			mutType: mutator.ConditionalsBoundary,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			viperSet(map[string]any{})
+			defer viperReset()
+
+			mod := gomodule.GoModule{
+				Name:       "example.com",
+				Root:       ".",
+				CallingDir: ".",
+			}
+			holder := &commandHolder{}
+			mjd := engine.NewExecutorDealer(mod, newWdDealerStub(t), expectedTimeout,
+				engine.WithExecContext(fakeExecCommandSuccessWithHolder(holder)))
+			mut := &mutantStub{
+				status:  mutator.Runnable,
+				mutType: tc.mutType,
+				pkg:     "example.com",
+			}
+			outCh := make(chan mutator.Mutator)
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+			executor := mjd.NewExecutor(mut, outCh, &wg)
+			go func() {
+				<-outCh
+				close(outCh)
+			}()
+			executor.Start(&workerpool.Worker{Name: "test", ID: 1})
+			wg.Wait()
+
+			got := strings.Join(holder.args, " ")
+			if strings.Contains(got, "-race") != tc.wantRace {
+				t.Errorf("expected -race to be %v, got %q", tc.wantRace, got)
+			}
+		})
+	}
+}
+
+func TestMutatorRunsWithOverlay(t *testing.T) {
+	viperSet(map[string]any{configuration.UnleashOverlayKey: true})
+	defer viperReset()
+
+	root := t.TempDir()
+	mod := gomodule.GoModule{
+		Name:       "example.com",
+		Root:       root,
+		CallingDir: ".",
+	}
+	wdDealer := newWdDealerStub(t)
+	wdDealer.fnGet = func(_ string) (string, error) {
+		t.Error("expected no copy of the module")
+
+		return t.TempDir(), nil
+	}
+	var gotArgs []string
+	var gotOverlay struct {
+		Replace map[string]string
+	}
+	execContext := func(ctx context.Context, command string, args ...string) *exec.Cmd {
+		gotArgs = args
+		for i, a := range args {
+			if a == "-overlay" && i+1 < len(args) {
+				data, err := os.ReadFile(args[i+1])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := json.Unmarshal(data, &gotOverlay); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		return fakeExecCommandSuccess(ctx, command, args...)
+	}
+	mjd := engine.NewExecutorDealer(mod, wdDealer, expectedTimeout, engine.WithExecContext(execContext))
+	mut := &overlayMutantStub{mutantStub: mutantStub{
+		status:   mutator.Runnable,
+		mutType:  mutator.ConditionalsBoundary,
+		pkg:      "example.com",
+		position: token.Position{Filename: "file.go", Line: 10, Column: 3},
+	}}
+	outCh := make(chan mutator.Mutator)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	executor := mjd.NewExecutor(mut, outCh, &wg)
+	go func() {
+		<-outCh
+		close(outCh)
+	}()
+	executor.Start(&workerpool.Worker{Name: "test", ID: 1})
+	wg.Wait()
+
+	want := map[string]string{filepath.Join(root, "file.go"): mut.appliedTo}
+	if !cmp.Equal(gotOverlay.Replace, want) {
+		t.Error(cmp.Diff(want, gotOverlay.Replace))
+	}
+	if !strings.Contains(strings.Join(gotArgs, " "), "-overlay ") {
+		t.Errorf("expected the -overlay flag, got %q", gotArgs)
+	}
+	if mut.applyCalled || mut.rollbackCalled {
+		t.Error("expected the source not to be mutated")
+	}
+	if mut.Status() != mutator.Lived {
+		t.Errorf("expected mutation to be %v, got %v", mutator.Lived, mut.Status())
+	}
+	if _, err := os.Stat(filepath.Dir(mut.appliedTo)); !os.IsNotExist(err) {
+		t.Errorf("expected the overlay folder to be removed, got %v", err)
+	}
+}
+
 func TestCPU(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -413,6 +665,160 @@ func TestCPU(t *testing.T) {
 	}
 }
 
+func TestMutatorRunWithPackageTimeout(t *testing.T) {
+	pkgElapsed := map[string]time.Duration{
+		"example.com/slow": 20 * time.Second,
+		"example.com/fast": 100 * time.Millisecond,
+	}
+	defaultTimeout := expectedTimeout * engine.DefaultTimeoutCoefficient
+	testCases := []struct {
+		name        string
+		pkg         string
+		set         map[string]any
+		wantTimeout time.Duration
+		calibrated  bool
+	}{
+		{
+			name:        "it multiplies the time of the package by the coefficient",
+			pkg:         "example.com/slow",
+			wantTimeout: 60 * time.Second,
+			calibrated:  true,
+		},
+		{
+			name:        "it gives at least the default floor",
+			pkg:         "example.com/fast",
+			wantTimeout: engine.DefaultTimeoutFloor,
+			calibrated:  true,
+		},
+		{
+			name:        "it can override the floor",
+			pkg:         "example.com/fast",
+			set:         map[string]any{configuration.UnleashTimeoutFloorKey: 2},
+			wantTimeout: 2 * time.Second,
+			calibrated:  true,
+		},
+		{
+			name:        "it gives at most the ceiling",
+			pkg:         "example.com/slow",
+			set:         map[string]any{configuration.UnleashTimeoutCeilingKey: 30},
+			wantTimeout: 30 * time.Second,
+			calibrated:  true,
+		},
+		{
+			name:        "it uses the coverage run for the packages not timed",
+			pkg:         "example.com/other",
+			wantTimeout: 2*time.Second + defaultTimeout,
+		},
+		{
+			name:        "it uses the coverage run in integration mode",
+			pkg:         "example.com/slow",
+			set:         map[string]any{configuration.UnleashIntegrationMode: true},
+			wantTimeout: 2*time.Second + defaultTimeout,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			viperSet(tc.set)
+			defer viperReset()
+
+			mod := gomodule.GoModule{
+				Name:       "example.com",
+				Root:       ".",
+				CallingDir: ".",
+			}
+			holder := &commandHolder{}
+			mjd := engine.NewExecutorDealer(mod, newWdDealerStub(t), expectedTimeout,
+				engine.WithExecContext(fakeExecCommandSuccessWithHolder(holder)),
+				engine.WithPackagesElapsed(pkgElapsed))
+			mut := &mutantStub{
+				status:  mutator.Runnable,
+				mutType: mutator.ConditionalsBoundary,
+				pkg:     tc.pkg,
+			}
+			outCh := make(chan mutator.Mutator)
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+			executor := mjd.NewExecutor(mut, outCh, &wg)
+			go func() {
+				<-outCh
+				close(outCh)
+			}()
+			executor.Start(&workerpool.Worker{Name: "test", ID: 1})
+			wg.Wait()
+
+			got := strings.Join(holder.args, " ")
+			want := fmt.Sprintf("-timeout %s ", tc.wantTimeout)
+			if !strings.Contains(got, want) {
+				t.Errorf("expected %q in %q", want, got)
+			}
+
+			// With a package timeout, the timeout of gremlins leaves the
+			// time to build the tests.
+			wantCtxTimeout := defaultTimeout
+			if tc.calibrated {
+				wantCtxTimeout += tc.wantTimeout
+			}
+			if absTimeDiff(holder.timeout, wantCtxTimeout) > time.Second {
+				t.Errorf("expected the timeout of gremlins to be %s, got %s", wantCtxTimeout, holder.timeout)
+			}
+		})
+	}
+}
+
+func TestMutatorTimedOutByGoTest(t *testing.T) {
+	testCases := []struct {
+		name       string
+		pkgElapsed map[string]time.Duration
+		wantStatus mutator.Status
+	}{
+		{
+			name:       "the tests reaching the package timeout are TIMED OUT",
+			pkgElapsed: map[string]time.Duration{"example.com": time.Second},
+			wantStatus: mutator.TimedOut,
+		},
+		{
+			name:       "without package timeout the tests failing are KILLED",
+			wantStatus: mutator.Killed,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			viperSet(map[string]any{})
+			defer viperReset()
+
+			mod := gomodule.GoModule{
+				Name:       "example.com",
+				Root:       ".",
+				CallingDir: ".",
+			}
+			mjd := engine.NewExecutorDealer(mod, newWdDealerStub(t), expectedTimeout,
+				engine.WithExecContext(fakeExecCommandTestsTimeout),
+				engine.WithPackagesElapsed(tc.pkgElapsed))
+			mut := &mutantStub{
+				status:  mutator.Runnable,
+				mutType: mutator.ConditionalsBoundary,
+				pkg:     "example.com",
+			}
+			outCh := make(chan mutator.Mutator)
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+			executor := mjd.NewExecutor(mut, outCh, &wg)
+			go func() {
+				<-outCh
+				close(outCh)
+			}()
+			executor.Start(&workerpool.Worker{Name: "test", ID: 1})
+			wg.Wait()
+
+			if mut.Status() != tc.wantStatus {
+				t.Errorf("expected mutation to be %v, got %v", tc.wantStatus, mut.Status())
+			}
+		})
+	}
+}
+
 func absTimeDiff(a, b time.Duration) time.Duration {
 	if a > b {
 		return a - b
@@ -442,6 +848,14 @@ func TestProcessBuildFailure(_ *testing.T) {
 	os.Exit(2) // skipcq: RVV-A0003
 }
 
+func TestProcessTestsTimeout(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+	fmt.Println("panic: test timed out after 5s")
+	os.Exit(1) // skipcq: RVV-A0003
+}
+
 func TestMutatorRunInTheCorrectFolder(t *testing.T) {
 	t.Run("mutation should run in the correct folder", func(t *testing.T) {
 		callingDir := "test/dir"
@@ -585,6 +999,13 @@ func fakeExecCommandBuildFailure(ctx context.Context, command string, args ...st
 	return getCmd(ctx, cs)
 }
 
+func fakeExecCommandTestsTimeout(ctx context.Context, command string, args ...string) *exec.Cmd {
+	cs := []string{"-test.run=TestProcessTestsTimeout", "--", command}
+	cs = append(cs, args...)
+
+	return getCmd(ctx, cs)
+}
+
 func getCmd(ctx context.Context, cs []string) *exec.Cmd {
 	// #nosec G204 - We are in tests, we don't care
 	cmd := exec.CommandContext(ctx, os.Args[0], cs...)
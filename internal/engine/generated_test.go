@@ -0,0 +1,128 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/log"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const generatedSource = `// Code generated by stringer -type=Level; DO NOT EDIT.
+
+package gen
+
+func (l Level) String() string {
+	if l > 0 {
+		return "high"
+	}
+
+	return "low"
+}
+`
+
+const notGeneratedSource = `package gen
+
+// The comment below is not a header, as it is after the package clause.
+
+// Code generated by hand; DO NOT EDIT.
+
+type Level int
+
+func Above(a, b int) bool {
+	return a > b
+}
+`
+
+func TestGeneratedFiles(t *testing.T) {
+	testCases := []struct {
+		name      string
+		include   bool
+		dryRun    bool
+		wantFiles []string
+		wantLog   string
+	}{
+		{
+			name:      "skips the generated files",
+			wantFiles: []string{"gen.go"},
+			wantLog:   "Skipped 1 generated files\n",
+		},
+		{
+			name:      "lists the generated files in dry-run",
+			dryRun:    true,
+			wantFiles: []string{"gen.go"},
+			wantLog:   "Skipped 1 generated files\n  level_string.go\n",
+		},
+		{
+			name:      "includes the generated files",
+			include:   true,
+			wantFiles: []string{"gen.go", "level_string.go"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mod := moduleWithSource(t, "gen", notGeneratedSource)
+			if err := os.WriteFile(filepath.Join(mod.Root, "level_string.go"), []byte(generatedSource), 0600); err != nil {
+				t.Fatal(err)
+			}
+			// Only the LIVED mutants are logged, so the log only contains
+			// the report of the generated files.
+			viperSet(map[string]any{
+				configuration.UnleashDryRunKey:           tc.dryRun,
+				configuration.UnleashIncludeGeneratedKey: tc.include,
+				configuration.UnleashOutputStatusesKey:   "l",
+			})
+			defer viperReset()
+			out := &bytes.Buffer{}
+			log.Init(out, &bytes.Buffer{})
+			defer log.Reset()
+
+			mut := engine.New(mod, engine.CodeData{}, newJobDealerStub(t))
+			res := mut.Run(context.Background())
+
+			files := make(map[string]bool)
+			for _, m := range res.Mutants {
+				if m.Type() == mutator.ConditionalsBoundary {
+					files[m.Position().Filename] = true
+				}
+			}
+			var got []string
+			for f := range files {
+				got = append(got, f)
+			}
+			sort.Strings(got)
+
+			if !cmp.Equal(got, tc.wantFiles) {
+				t.Error(cmp.Diff(tc.wantFiles, got))
+			}
+			if out.String() != tc.wantLog {
+				t.Error(cmp.Diff(tc.wantLog, out.String()))
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"github.com/singhnishant94/gremlins/internal/astutil"
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// findLiteralMutations looks for the integer, string and boolean literals
+// of the file. The literals of the constant declarations, of the imports, of
+// the struct tags, of the array lengths and of the constant switch cases are
+// not mutated, as they would often not compile, like a duplicate case.
+func (mu *Engine) findLiteralMutations(pkg string, set *token.FileSet, file *ast.File, info *types.Info) {
+	skip := make(map[ast.Node]bool)
+	ast.Inspect(file, func(node ast.Node) bool {
+		// Only the statements are checked, as the identifiers without an
+		// object, such as true and false, are arid.
+		if stmt, ok := node.(ast.Stmt); ok && detectAridNodes && astutil.IsAridNode(stmt, info) {
+			return false
+		}
+		if skip[node] {
+			return false
+		}
+		switch n := node.(type) {
+		case *ast.GenDecl:
+			return n.Tok != token.CONST && n.Tok != token.IMPORT
+		case *ast.Field:
+			if n.Tag != nil {
+				skip[n.Tag] = true
+			}
+		case *ast.SelectorExpr:
+			skip[n.Sel] = true
+		case *ast.ArrayType:
+			if n.Len != nil {
+				skip[n.Len] = true
+			}
+		case *ast.CaseClause:
+			for _, e := range n.List {
+				if isConstExpr(e, info) {
+					skip[e] = true
+				}
+			}
+		case *ast.BasicLit:
+			mu.findBasicLitMutations(pkg, set, file, n)
+		case *ast.Ident:
+			if isBoolLiteral(n, info) {
+				flipped := "true"
+				if n.Name == "true" {
+					flipped = "false"
+				}
+				mu.addLiteral(pkg, set, file, mutator.BooleanLiteral, n, ast.NewIdent(flipped), replaceString(&n.Name, flipped))
+			}
+		}
+
+		return true
+	})
+}
+
+func (mu *Engine) findBasicLitMutations(pkg string, set *token.FileSet, file *ast.File, lit *ast.BasicLit) {
+	switch lit.Kind {
+	case token.INT:
+		for _, v := range intReplacements(lit.Value) {
+			mu.addLiteral(pkg, set, file, mutator.NumericLiteral, lit, &ast.BasicLit{Kind: token.INT, Value: v}, replaceString(&lit.Value, v))
+		}
+	case token.STRING:
+		if s, err := strconv.Unquote(lit.Value); err != nil || s == "" {
+			return
+		}
+		mu.addLiteral(pkg, set, file, mutator.StringLiteral, lit, &ast.BasicLit{Kind: token.STRING, Value: `""`}, replaceString(&lit.Value, `""`))
+	}
+}
+
+func (mu *Engine) addLiteral(pkg string, set *token.FileSet, file *ast.File, mt mutator.Type, node, replacement ast.Node, mutation Mutation) {
+	if !configuration.Get[bool](configuration.MutantTypeEnabledKey(mt)) {
+		return
+	}
+	m := NewASTMutator(pkg, set, file, node, replacement, mutation)
+	m.SetType(mt)
+	m.SetStatus(mu.mutationStatus(set.Position(node.Pos())))
+
+	mu.mutants = append(mu.mutants, m)
+}
+
+// intReplacements returns the values which replace the integer literal:
+// 0, 1 and the literal plus one, leaving out the ones equal to the literal.
+func intReplacements(lit string) []string {
+	v := constant.MakeFromLiteral(lit, token.INT, 0)
+	if v.Kind() != constant.Int {
+		return nil
+	}
+	candidates := []constant.Value{
+		constant.MakeInt64(0),
+		constant.MakeInt64(1),
+		constant.BinaryOp(v, token.ADD, constant.MakeInt64(1)),
+	}
+	var res []string
+	for i, c := range candidates {
+		if constant.Compare(c, token.EQL, v) || (i == 2 && constant.Compare(c, token.EQL, candidates[1])) {
+			continue
+		}
+		res = append(res, c.ExactString())
+	}
+
+	return res
+}
+
+// isConstExpr checks if the expression is a constant. Without the type
+// information, only the expressions made of literals are recognised.
+func isConstExpr(e ast.Expr, info *types.Info) bool {
+	if info != nil {
+		tv, ok := info.Types[e]
+
+		return ok && tv.Value != nil
+	}
+	isConst := true
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil, *ast.BasicLit, *ast.BinaryExpr, *ast.UnaryExpr, *ast.ParenExpr:
+		default:
+			isConst = false
+		}
+
+		return isConst
+	})
+
+	return isConst
+}
+
+// isBoolLiteral checks if the identifier is one of the predeclared true and
+// false. Without the type information, only the name can be checked.
+func isBoolLiteral(id *ast.Ident, info *types.Info) bool {
+	if id.Name != "true" && id.Name != "false" {
+		return false
+	}
+	if info == nil {
+		return true
+	}
+
+	return info.Uses[id] == types.Universe.Lookup(id.Name)
+}
+
+// replaceString replaces the string the pointer refers to, which is the
+// value of a literal or the name of an identifier.
+func replaceString(s *string, with string) Mutation {
+	return func() func() {
+		orig := *s
+		*s = with
+
+		return func() {
+			*s = orig
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const literalsSource = `package lits
+
+import "fmt"
+
+const Retries = 3
+
+type Config struct {
+	Name string ` + "`json:\"name\"`" + `
+	Size int
+}
+
+func New() Config {
+	size := 0
+	for i := 0; i < 5; i++ {
+		size += 1024
+	}
+	debug := false
+	if debug {
+		fmt.Println("")
+	}
+
+	return Config{Name: fmt.Sprint("gremlins", Retries), Size: size}
+}
+
+func Enabled() bool {
+	return true
+}
+
+func Shadowed() int {
+	true := 1
+	return true
+}
+
+func Day(n int) string {
+	var names [7]string
+	switch n {
+	case 0, 6:
+		return names[n]
+	}
+	return names[n-1]
+}
+`
+
+var literalTypes = map[mutator.Type]bool{
+	mutator.NumericLiteral: true,
+	mutator.StringLiteral:  true,
+	mutator.BooleanLiteral: true,
+}
+
+func TestLiteralMutations(t *testing.T) {
+	mod := moduleWithSource(t, "lits", literalsSource)
+	mutants := dryRun(t, mod, true, nil)
+
+	// The constant, the struct tag, the import, the empty string, the
+	// shadowed true, the array length and the switch cases are not mutated.
+	want := []string{
+		"13 NUMERIC_LITERAL 1",
+		"14 NUMERIC_LITERAL 0",
+		"14 NUMERIC_LITERAL 1",
+		"14 NUMERIC_LITERAL 1",
+		"14 NUMERIC_LITERAL 6",
+		"15 NUMERIC_LITERAL 0",
+		"15 NUMERIC_LITERAL 1",
+		"15 NUMERIC_LITERAL 1025",
+		"17 BOOLEAN_LITERAL true",
+		"22 STRING_LITERAL \"\"",
+		"26 BOOLEAN_LITERAL false",
+		"30 NUMERIC_LITERAL 0",
+		"30 NUMERIC_LITERAL 2",
+		"40 NUMERIC_LITERAL 0",
+		"40 NUMERIC_LITERAL 2",
+	}
+	got := describeMutants(mutants, literalTypes)
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestLiteralMutationsWithoutTypes(t *testing.T) {
+	mod := moduleWithSource(t, "lits", literalsSource)
+	mutants := dryRun(t, mod, false, nil)
+
+	// Without the type information, the shadowed true can't be told apart
+	// from the predeclared one.
+	var shadowed int
+	for _, m := range mutants {
+		if m.Type() == mutator.BooleanLiteral && m.Position().Line >= 30 {
+			shadowed++
+		}
+	}
+	if shadowed != 2 {
+		t.Errorf("expected the shadowed true to be mutated twice, got %d", shadowed)
+	}
+}
+
+func TestLiteralMutationsCompile(t *testing.T) {
+	mod := moduleWithSource(t, "lits", literalsSource)
+
+	if got := applyAndBuild(t, mod, literalTypes); len(got) == 0 {
+		t.Error("expected literal mutants to be applied")
+	}
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+)
+
+// renderMutant prints the file of a mutant without and with its Mutation.
+// Since the AST is shared among mutants, the Mutation is applied and rolled
+// back holding the lock of the file, and only for the time needed to print
+// it. The mutated source is nil if the Mutation has nothing to change, that
+// is if it returns no rollback.
+func renderMutant(set *token.FileSet, file *ast.File, mutation Mutation) (orig, mutated []byte, err error) {
+	lock := fileLock(set.Position(file.Package).Filename)
+	lock.Lock()
+	defer lock.Unlock()
+
+	orig, err = printFile(set, file)
+	if err != nil {
+		return nil, nil, err
+	}
+	rollback := mutation()
+	if rollback == nil {
+		return orig, nil, nil
+	}
+	mutated, err = printFile(set, file)
+	rollback()
+
+	return orig, mutated, err
+}
+
+// applyMutant writes the mutated file of a mutant to the given path and
+// returns its diff. The prerendered source is used if there is one, so that
+// no file lock is needed, otherwise the file is rendered by renderMutant.
+// The file is not written if the Mutation has nothing to change.
+func applyMutant(filename string, rendered *renderedSource, set *token.FileSet, file *ast.File, mutation Mutation) (string, error) {
+	if rendered != nil {
+		return writeMutant(filename, rendered.orig, rendered.mutated())
+	}
+	orig, mutated, err := renderMutant(set, file, mutation)
+	if err != nil || mutated == nil {
+		return "", err
+	}
+
+	return writeMutant(filename, orig, mutated)
+}
+
+// renderedSource is the mutated source of a mutant rendered ahead of the
+// run. It is kept as the change to the printed original source, which is
+// shared by all the mutants of the file.
+type renderedSource struct {
+	orig []byte
+	// text replaces the bytes of orig from start to end.
+	text       []byte
+	start, end int
+}
+
+func (r *renderedSource) mutated() []byte {
+	mutated := make([]byte, 0, r.start+len(r.text)+len(r.orig)-r.end)
+	mutated = append(mutated, r.orig[:r.start]...)
+	mutated = append(mutated, r.text...)
+
+	return append(mutated, r.orig[r.end:]...)
+}
+
+// renderedSources caches the printed original source of each file, while
+// the mutants are prerendered.
+type renderedSources map[*ast.File][]byte
+
+// render renders the source of a mutant without the file lock, so it must
+// not run while the mutants are executed. It returns nil if the rendering
+// fails or the Mutation has nothing to change, leaving the rendering to
+// applyMutant.
+func (rs renderedSources) render(set *token.FileSet, file *ast.File, mutation Mutation) *renderedSource {
+	orig, ok := rs[file]
+	if !ok {
+		var err error
+		orig, err = printFile(set, file)
+		if err != nil {
+			return nil
+		}
+		rs[file] = orig
+	}
+	rollback := mutation()
+	if rollback == nil {
+		return nil
+	}
+	mutated, err := printFile(set, file)
+	rollback()
+	if err != nil {
+		return nil
+	}
+
+	start := 0
+	for start < len(orig) && start < len(mutated) && orig[start] == mutated[start] {
+		start++
+	}
+	end, mEnd := len(orig), len(mutated)
+	for end > start && mEnd > start && orig[end-1] == mutated[mEnd-1] {
+		end--
+		mEnd--
+	}
+
+	// The text is copied, so that the mutated source is not kept in memory.
+	text := append([]byte(nil), mutated[start:mEnd]...)
+
+	return &renderedSource{orig: orig, text: text, start: start, end: end}
+}
+
+func printFile(set *token.FileSet, file *ast.File) ([]byte, error) {
+	w := &bytes.Buffer{}
+	if err := printer.Fprint(w, set, file); err != nil {
+		return nil, err
+	}
+
+	return w.Bytes(), nil
+}
+
+// writeMutant writes the mutated source to the given path and returns its
+// diff with the original one.
+func writeMutant(filename string, orig, mutated []byte) (string, error) {
+	// Create a copy of the original file to calculate the diff later.
+	copyOrigFileName := filename + ".copy.orig"
+	if err := os.WriteFile(copyOrigFileName, orig, 0600); err != nil {
+		return "", err
+	}
+	// Remove the copy of the original file.
+	defer os.Remove(copyOrigFileName)
+
+	if err := os.WriteFile(filename, mutated, 0600); err != nil {
+		return "", err
+	}
+
+	return calcDiff(copyOrigFileName, filename), nil
+}
+
+func calcDiff(origFile, mutationFile string) string {
+	diff, err := exec.Command("diff", "--label=Original", "--label=New", "-u", origFile, mutationFile).CombinedOutput()
+	var execExitCode int
+	if err == nil {
+		execExitCode = 0
+	} else if e, ok := err.(*exec.ExitError); ok {
+		execExitCode = e.Sys().(syscall.WaitStatus).ExitStatus()
+	} else {
+		panic(err)
+	}
+	if execExitCode != 0 && execExitCode != 1 {
+		fmt.Printf("%s\n", diff)
+
+		panic("Could not execute diff on mutation file")
+	}
+
+	return string(diff)
+}
+
+var locks = make(map[string]*sync.Mutex)
+var mutex sync.RWMutex
+
+func fileLock(filename string) *sync.Mutex {
+	lock, ok := cachedLock(filename)
+	if !ok {
+		mutex.Lock()
+		defer mutex.Unlock()
+		lock, ok = locks[filename]
+		if !ok {
+			lock = &sync.Mutex{}
+			locks[filename] = lock
+
+			return lock
+		}
+
+		return lock
+	}
+
+	return lock
+}
+
+func cachedLock(filename string) (*sync.Mutex, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	lock, ok := locks[filename]
+
+	return lock, ok
+}
@@ -83,7 +83,7 @@ func NewNode(nd ast.Node) (*Node, bool) {
 	var pos token.Pos
 	var node *ast.Node
 	switch n := nd.(type) {
-	case *ast.BlockStmt, *ast.CaseClause:
+	case *ast.BlockStmt, *ast.CaseClause, *ast.IfStmt, *ast.ForStmt, *ast.SwitchStmt:
 		node = &nd
 		pos = n.Pos()
 	default:
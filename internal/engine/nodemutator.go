@@ -17,15 +17,10 @@
 package engine
 
 import (
-	"bytes"
-	"fmt"
 	"go/ast"
-	"go/printer"
 	"go/token"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"syscall"
 
 	"github.com/singhnishant94/gremlins/internal/astutil"
 	"github.com/singhnishant94/gremlins/internal/mutator"
@@ -47,6 +42,7 @@ type StmtRemover struct {
 	file        *ast.File
 	node        *Node
 	workDir     string
+	rendered    *renderedSource
 	origFile    []byte
 	mutantType  mutator.Type
 	status      mutator.Status
@@ -115,106 +111,82 @@ func (m *StmtRemover) SetDiff(d string) {
 	m.diff = d
 }
 
-// Pkg returns the package name to which the mutant belongs.
-func (m *StmtRemover) Pkg() string {
-	return m.pkgName
+// Replacement returns an empty string, since the statement is removed.
+func (*StmtRemover) Replacement() string {
+	return ""
 }
 
-func (m *StmtRemover) Apply() error {
-	fileLock(m.Position().Filename).Lock()
-	defer fileLock(m.Position().Filename).Unlock()
-
-	filename := filepath.Join(m.workDir, m.fs.Position((*m.node.node).Pos()).Filename)
-	var err error
-	m.origFile, err = os.ReadFile(filename)
-	if err != nil {
-		return err
-	}
-
-	// Create a copy of the original file to calculate the diff later.
-	copyOrigFileName := filepath.Join(m.workDir, m.Position().Filename+".copy.orig")
-	if err = m.writeMutatedFile(copyOrigFileName); err != nil {
-		return err
-	}
-
-	// Statement block removal
+// Location returns the start and end token.Position of the removed
+// statement.
+func (m *StmtRemover) Location() (token.Position, token.Position) {
 	var l []ast.Stmt
-
 	switch n := (*m.node.node).(type) {
 	case *ast.BlockStmt:
 		l = n.List
 	case *ast.CaseClause:
 		l = n.Body
 	}
-
-	var oldStmt ast.Stmt
-
-	for i := range l {
-		if i == m.idx {
-			m.pos = l[i].Pos()
-			oldStmt = l[i]
-			l[i] = astutil.CreateNoopOfStatement(oldStmt)
-			break
-		}
-	}
-
-	if oldStmt == nil {
-		fmt.Println("OldStmt is nil. Returning.")
-		return nil
-	}
-
-	if err = m.writeMutatedFile(filename); err != nil {
-		return err
-	}
-
-	// Rollback
-	for i := range l {
-		if i == m.idx {
-			l[i] = oldStmt
-			break
-		}
+	if m.idx >= len(l) {
+		return m.Position(), m.Position()
 	}
 
-	m.SetDiff(m.calcDiff(copyOrigFileName, filename))
-
-	// Remove the copy of the original file.
-	os.Remove(copyOrigFileName)
+	return m.fs.Position(l[m.idx].Pos()), m.fs.Position(l[m.idx].End())
+}
 
-	return nil
+// Pkg returns the package name to which the mutant belongs.
+func (m *StmtRemover) Pkg() string {
+	return m.pkgName
 }
 
-func (m *StmtRemover) writeMutatedFile(filename string) error {
-	w := &bytes.Buffer{}
-	err := printer.Fprint(w, m.fs, m.file)
+func (m *StmtRemover) Apply() error {
+	filename := filepath.Join(m.workDir, m.fs.Position((*m.node.node).Pos()).Filename)
+	var err error
+	m.origFile, err = os.ReadFile(filename)
 	if err != nil {
 		return err
 	}
 
-	err = os.WriteFile(filename, w.Bytes(), 0600)
+	return m.ApplyTo(filename)
+}
+
+// ApplyTo writes the mutated file to the given path and sets the diff, as
+// TokenMutator.ApplyTo does.
+func (m *StmtRemover) ApplyTo(filename string) error {
+	diff, err := applyMutant(filename, m.rendered, m.fs, m.file, m.mutate)
 	if err != nil {
 		return err
 	}
+	m.SetDiff(diff)
 
 	return nil
 }
 
-func (m *StmtRemover) calcDiff(origFile, mutationFile string) string {
-	diff, err := exec.Command("diff", "--label=Original", "--label=New", "-u", origFile, mutationFile).CombinedOutput()
-	var execExitCode int
-	if err == nil {
-		execExitCode = 0
-	} else if e, ok := err.(*exec.ExitError); ok {
-		execExitCode = e.Sys().(syscall.WaitStatus).ExitStatus()
-	} else {
-		panic(err)
-	}
-	if execExitCode != 0 && execExitCode != 1 {
-		fmt.Printf("%s\n", diff)
+// prerender renders the mutated source ahead of the run, so that ApplyTo
+// doesn't need to lock the file.
+func (m *StmtRemover) prerender(rs renderedSources) {
+	m.rendered = rs.render(m.fs, m.file, m.mutate)
+}
+
+// mutate replaces the statement with a noop one, which keeps its
+// identifiers used, and returns the function which puts it back.
+func (m *StmtRemover) mutate() func() {
+	var l []ast.Stmt
 
-		panic("Could not execute diff on mutation file")
+	switch n := (*m.node.node).(type) {
+	case *ast.BlockStmt:
+		l = n.List
+	case *ast.CaseClause:
+		l = n.Body
 	}
+	if m.idx >= len(l) {
+		return nil
+	}
+
+	oldStmt := l[m.idx]
+	m.pos = oldStmt.Pos()
+	l[m.idx] = astutil.CreateNoopOfStatement(oldStmt)
 
-	return string(diff)
+	return func() { l[m.idx] = oldStmt }
 }
 
 // Rollback puts back the original file after the test and cleans up the
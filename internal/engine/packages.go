@@ -0,0 +1,123 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/gomodule"
+)
+
+// loadMode type-checks the dependencies from source instead of reading their
+// export data, so that the loading doesn't depend on the export data format
+// of the Go toolchain in use.
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+	packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo
+
+// Packages contains the syntax trees and the type information of the
+// packages of a Go module, indexed by the file name relative to the
+// directory Gremlins is running from.
+//
+// All the files share the same token.FileSet, and the file names in the
+// token.FileSet are relative as well, so that they can be used to find the
+// coverage and the diff of the mutants.
+type Packages struct {
+	fset  *token.FileSet
+	files map[string]typedFile
+}
+
+type typedFile struct {
+	file *ast.File
+	info *types.Info
+}
+
+// LoadPackages loads all the packages under the directory Gremlins is
+// running from, with full type information.
+//
+// Packages with type errors are still loaded, their type information will
+// be partial.
+func LoadPackages(mod gomodule.GoModule) (*Packages, error) {
+	dir, err := filepath.Abs(filepath.Join(mod.Root, mod.CallingDir))
+	if err != nil {
+		return nil, err
+	}
+
+	var buildFlags []string
+	if tags := configuration.Get[string](configuration.UnleashTagsKey); tags != "" {
+		buildFlags = append(buildFlags, "-tags", tags)
+	}
+
+	fset := token.NewFileSet()
+	cfg := &packages.Config{
+		Mode:       loadMode,
+		Dir:        dir,
+		Env:        os.Environ(),
+		BuildFlags: buildFlags,
+		Fset:       fset,
+		ParseFile: func(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+			// Only the files of the module are made relative, the ones of
+			// the dependencies keep their path.
+			if rel, err := filepath.Rel(dir, filename); err == nil && filepath.IsLocal(rel) {
+				filename = rel
+			}
+
+			return parser.ParseFile(fset, filename, src, parser.ParseComments)
+		},
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("impossible to load packages: %w", err)
+	}
+
+	p := &Packages{
+		fset:  fset,
+		files: make(map[string]typedFile),
+	}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Syntax {
+			name := filepath.ToSlash(fset.Position(f.Package).Filename)
+			p.files[name] = typedFile{file: f, info: pkg.TypesInfo}
+		}
+	}
+
+	return p, nil
+}
+
+// File returns the syntax tree and the type information of the file. It
+// returns false if the file is not part of the loaded packages.
+func (p *Packages) File(fileName string) (*ast.File, *types.Info, bool) {
+	if p == nil {
+		return nil, nil, false
+	}
+	f, ok := p.files[filepath.ToSlash(fileName)]
+
+	return f.file, f.info, ok
+}
+
+// FileSet returns the token.FileSet shared by all the files.
+func (p *Packages) FileSet() *token.FileSet {
+	return p.fset
+}
@@ -0,0 +1,239 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"context"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/gomodule"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const typedSource = `package typed
+
+import (
+	"log"
+	"strings"
+)
+
+func Concat(a, b string) string {
+	return a + b
+}
+
+func Sum(a, b int) int {
+	return a + b
+}
+
+func Append(s string) string {
+	s += "x"
+
+	return s
+}
+
+func Ptr(a int) *int {
+	return &a
+}
+
+func And(a, b int) int {
+	return a & b
+}
+
+func Log(l *log.Logger, a int) {
+	if a > 0 {
+		l.Printf("%d", a)
+	}
+}
+
+func Write(log *strings.Builder, a int) {
+	if a < 0 {
+		log.WriteString("negative")
+	}
+}
+`
+
+func TestTypeAwareDiscovery(t *testing.T) {
+	mod := typedModule(t)
+	viperSet(map[string]any{configuration.UnleashDryRunKey: true})
+	defer viperReset()
+
+	pkgs, err := engine.LoadPackages(mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name       string
+		mutantType mutator.Type
+		line       int
+		withTypes  bool
+		found      bool
+	}{
+		{
+			name:       "ARITHMETIC_BASE is skipped on string concatenation",
+			mutantType: mutator.ArithmeticBase,
+			line:       9,
+			withTypes:  true,
+			found:      false,
+		},
+		{
+			name:       "ARITHMETIC_BASE is found on string concatenation without type information",
+			mutantType: mutator.ArithmeticBase,
+			line:       9,
+			withTypes:  false,
+			found:      true,
+		},
+		{
+			name:       "ARITHMETIC_BASE is found on integer sum",
+			mutantType: mutator.ArithmeticBase,
+			line:       13,
+			withTypes:  true,
+			found:      true,
+		},
+		{
+			name:       "INVERT_ASSIGNMENTS is skipped on string concatenation",
+			mutantType: mutator.InvertAssignments,
+			line:       17,
+			withTypes:  true,
+			found:      false,
+		},
+		{
+			name:       "REMOVE_SELF_ASSIGNMENTS is found on string concatenation",
+			mutantType: mutator.RemoveSelfAssignments,
+			line:       17,
+			withTypes:  true,
+			found:      true,
+		},
+		{
+			name:       "INVERT_BITWISE is skipped on address-of operator",
+			mutantType: mutator.InvertBitwise,
+			line:       23,
+			withTypes:  true,
+			found:      false,
+		},
+		{
+			name:       "INVERT_BITWISE is found on integers",
+			mutantType: mutator.InvertBitwise,
+			line:       27,
+			withTypes:  true,
+			found:      true,
+		},
+		{
+			name:       "logger calls are resolved by type",
+			mutantType: mutator.ConditionalsBoundary,
+			line:       31,
+			withTypes:  true,
+			found:      false,
+		},
+		{
+			name:       "logger calls are resolved by name without type information",
+			mutantType: mutator.ConditionalsBoundary,
+			line:       31,
+			withTypes:  false,
+			found:      true,
+		},
+		{
+			name:       "variables named like loggers are not arid",
+			mutantType: mutator.ConditionalsBoundary,
+			line:       37,
+			withTypes:  true,
+			found:      true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []engine.Option
+			if tc.withTypes {
+				opts = append(opts, engine.WithPackages(pkgs))
+			}
+			mut := engine.New(mod, engine.CodeData{}, newJobDealerStub(t), opts...)
+			res := mut.Run(context.Background())
+
+			found := false
+			for _, m := range res.Mutants {
+				if m.Type() == tc.mutantType && m.Position().Line == tc.line {
+					found = true
+
+					break
+				}
+			}
+			if found != tc.found {
+				t.Errorf("expected %s at line %d to be found: %t, got %t", tc.mutantType, tc.line, tc.found, found)
+			}
+		})
+	}
+}
+
+func TestLoadPackagesUsesRelativeFileNames(t *testing.T) {
+	mod := typedModule(t)
+
+	pkgs, err := engine.LoadPackages(mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, info, ok := pkgs.File("typed.go")
+	if !ok {
+		t.Fatal("expected typed.go to be loaded")
+	}
+	if info == nil {
+		t.Error("expected type information to be loaded")
+	}
+	if got := pkgs.FileSet().Position(file.Package).Filename; got != "typed.go" {
+		t.Errorf("expected file name to be relative, got %q", got)
+	}
+
+	// The files of the dependencies, loaded from source, keep their path.
+	pkgs.FileSet().Iterate(func(f *token.File) bool {
+		if !filepath.IsAbs(f.Name()) && f.Name() != "typed.go" {
+			t.Errorf("expected file name of a dependency to be absolute, got %q", f.Name())
+		}
+
+		return true
+	})
+}
+
+func typedModule(t *testing.T) gomodule.GoModule {
+	t.Helper()
+
+	return moduleWithSource(t, "typed", typedSource)
+}
+
+// moduleWithSource creates a module named example.com/<name> with a single
+// <name>.go file containing the source.
+func moduleWithSource(t *testing.T, name, src string) gomodule.GoModule {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/"+name+"\n\ngo 1.18\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return gomodule.GoModule{
+		Name:       "example.com/" + name,
+		Root:       dir,
+		CallingDir: ".",
+	}
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"github.com/singhnishant94/gremlins/internal/astutil"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// syntheticError is the message of the error returned in place of nil by
+// the ReturnError mutants.
+const syntheticError = "gremlins: synthetic error"
+
+// findReturns looks for the return statements of the function body. The
+// function literals are searched with their own signature.
+func (r *typedFinder) findReturns(body *ast.BlockStmt, sig *types.Signature) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		if detectAridNodes && astutil.IsAridNode(node, r.info) {
+			return false
+		}
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			if s, ok := r.info.TypeOf(n).(*types.Signature); ok {
+				r.findReturns(n.Body, s)
+			}
+
+			return false
+		case *ast.ReturnStmt:
+			// Naked returns and returns of multi-value calls are not mutated.
+			if len(n.Results) != sig.Results().Len() {
+				return true
+			}
+			for i := range n.Results {
+				r.findResultMutations(n, i, sig.Results().At(i).Type())
+			}
+		}
+
+		return true
+	})
+}
+
+func (r *typedFinder) findResultMutations(ret *ast.ReturnStmt, i int, t types.Type) {
+	expr := ret.Results[i]
+	tv := r.info.Types[expr]
+
+	switch {
+	case types.Identical(t, types.Universe.Lookup("error").Type()):
+		if tv.IsNil() {
+			r.findReturnError(ret, i)
+
+			return
+		}
+		if r.swallowed[expr] || r.removesLastUse(expr) {
+			return
+		}
+		nilErr := ast.NewIdent("nil")
+		r.add(mutator.ReturnNilError, expr, nilErr, replaceExpr(&ret.Results[i], nilErr))
+	case isBoolean(t):
+		negated := negate(expr)
+		r.add(mutator.ReturnNegateBool, expr, negated, replaceExpr(&ret.Results[i], negated))
+	default:
+		if r.removesLastUse(expr) {
+			return
+		}
+		if !isZeroValue(expr, tv) {
+			if zero, ok := r.zeroValue(t); ok {
+				r.add(mutator.ReturnZeroValue, expr, zero, replaceExpr(&ret.Results[i], zero))
+			}
+		}
+		if isCollection(t) && !tv.IsNil() && !isEmptyLiteral(expr) {
+			if typ, ok := r.typeExpr(t); ok {
+				empty := &ast.CompositeLit{Type: typ}
+				r.add(mutator.ReturnEmptyCollection, expr, empty, replaceExpr(&ret.Results[i], empty))
+			}
+		}
+	}
+}
+
+// findReturnError replaces a nil error with a new one, created with the
+// errors package. If the file doesn't import it, the import is added as
+// part of the mutation.
+func (r *typedFinder) findReturnError(ret *ast.ReturnStmt, i int) {
+	name, imported, ok := r.errorsPkg()
+	if !ok {
+		return
+	}
+	newErr := &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: ast.NewIdent(name), Sel: ast.NewIdent("New")},
+		Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(syntheticError)}},
+	}
+	mutation := replaceExpr(&ret.Results[i], newErr)
+	if !imported {
+		mutation = withImport(r.file, "errors", mutation)
+	}
+	r.add(mutator.ReturnError, ret.Results[i], newErr, mutation)
+}
+
+// errorsPkg returns the name under which the errors package can be used in
+// the file, and whether the file already imports it. It returns false if
+// the package can't be used without conflicting with other identifiers.
+func (r *typedFinder) errorsPkg() (string, bool, bool) {
+	for _, imp := range r.file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := importName(imp, path)
+		if path == "errors" && name != "_" && name != "." {
+			return name, true, true
+		}
+		if name == "errors" {
+			return "", false, false
+		}
+	}
+	if r.pkg.Scope().Lookup("errors") != nil {
+		return "", false, false
+	}
+
+	return "errors", false, true
+}
+
+// zeroValue returns the expression of the zero value of the type. It
+// returns false if the type can't be written in the file.
+func (r *typedFinder) zeroValue(t types.Type) (ast.Expr, bool) {
+	if _, ok := t.(*types.TypeParam); ok {
+		typ, ok := r.typeExpr(t)
+		if !ok {
+			return nil, false
+		}
+
+		return &ast.StarExpr{X: &ast.CallExpr{Fun: ast.NewIdent("new"), Args: []ast.Expr{typ}}}, true
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return ast.NewIdent("false"), true
+		case u.Info()&types.IsString != 0:
+			return &ast.BasicLit{Kind: token.STRING, Value: `""`}, true
+		case u.Info()&types.IsNumeric != 0:
+			return &ast.BasicLit{Kind: token.INT, Value: "0"}, true
+		default:
+			return ast.NewIdent("nil"), true
+		}
+	case *types.Struct, *types.Array:
+		typ, ok := r.typeExpr(t)
+		if !ok {
+			return nil, false
+		}
+
+		return &ast.CompositeLit{Type: typ}, true
+	default:
+		return ast.NewIdent("nil"), true
+	}
+}
+
+func negate(expr ast.Expr) ast.Expr {
+	if _, ok := expr.(*ast.BinaryExpr); ok {
+		expr = &ast.ParenExpr{X: expr}
+	}
+
+	return &ast.UnaryExpr{Op: token.NOT, X: expr}
+}
+
+func isBoolean(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+
+	return ok && b.Info()&types.IsBoolean != 0
+}
+
+func isCollection(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+func isEmptyLiteral(expr ast.Expr) bool {
+	lit, ok := expr.(*ast.CompositeLit)
+
+	return ok && len(lit.Elts) == 0
+}
+
+// isZeroValue checks if the returned expression is already the zero value,
+// in which case replacing it would not change the code.
+func isZeroValue(expr ast.Expr, tv types.TypeAndValue) bool {
+	if tv.IsNil() || isEmptyLiteral(expr) {
+		return true
+	}
+	if tv.Value == nil {
+		return false
+	}
+	switch tv.Value.Kind() {
+	case constant.Bool:
+		return !constant.BoolVal(tv.Value)
+	case constant.String:
+		return constant.StringVal(tv.Value) == ""
+	case constant.Int, constant.Float, constant.Complex:
+		return constant.Sign(tv.Value) == 0
+	default:
+		return false
+	}
+}
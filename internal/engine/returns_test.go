@@ -0,0 +1,157 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const returnsSource = `package returns
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Point struct{ X, Y int }
+
+type Celsius float64
+
+func Zero(a int) int {
+	return a + 1
+}
+
+func AlreadyZero() (int, string) {
+	return 0, ""
+}
+
+func Pos(a int) Point {
+	return Point{X: a}
+}
+
+func Temp(a float64) Celsius {
+	return Celsius(a)
+}
+
+func Positive(a int) bool {
+	return a > 0
+}
+
+func Parse(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func Names(m map[string]int) []string {
+	var names []string
+	for k := range m {
+		names = append(names, k)
+	}
+
+	return names
+}
+
+func Index() map[string]int {
+	return map[string]int{}
+}
+
+func First[T any](s []T) T {
+	return s[0]
+}
+
+func Closure() func() bool {
+	return func() bool {
+		return true
+	}
+}
+
+func Itoa(a int) string {
+	return strconv.Itoa(a)
+}
+
+func Upper(s string) string {
+	return strings.ToUpper(s)
+}
+`
+
+var returnTypes = map[mutator.Type]bool{
+	mutator.ReturnZeroValue:       true,
+	mutator.ReturnNegateBool:      true,
+	mutator.ReturnNilError:        true,
+	mutator.ReturnError:           true,
+	mutator.ReturnEmptyCollection: true,
+}
+
+func TestReturnMutations(t *testing.T) {
+	mod := moduleWithSource(t, "returns", returnsSource)
+	// The errors returned by the error checks are swallowed by the
+	// SWALLOW_ERROR mutants when enabled.
+	mutants := dryRun(t, mod, true, map[string]any{
+		configuration.MutantTypeEnabledKey(mutator.SwallowError): false,
+	})
+
+	// The n returned at line 38 and the strings.ToUpper returned at line 69
+	// are not replaced, as they are the only use of a variable and of an
+	// import.
+	want := []string{
+		"13 RETURN_ZERO_VALUE 0",
+		"21 RETURN_ZERO_VALUE Point{}",
+		"25 RETURN_ZERO_VALUE 0",
+		"29 RETURN_NEGATE_BOOL !(a > 0)",
+		"35 RETURN_NIL_ERROR nil",
+		"38 RETURN_ERROR errors.New(\"gremlins: synthetic error\")",
+		"47 RETURN_EMPTY_COLLECTION []string{}",
+		"47 RETURN_ZERO_VALUE nil",
+		"55 RETURN_ZERO_VALUE *new(T)",
+		"59 RETURN_ZERO_VALUE nil",
+		"60 RETURN_NEGATE_BOOL !true",
+		"65 RETURN_ZERO_VALUE \"\"",
+	}
+	got := describeMutants(mutants, returnTypes)
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestReturnMutationsWithoutTypes(t *testing.T) {
+	mod := moduleWithSource(t, "returns", returnsSource)
+	mutants := dryRun(t, mod, false, nil)
+
+	if got := describeMutants(mutants, returnTypes); len(got) != 0 {
+		t.Errorf("expected no return mutants without type information, got %v", got)
+	}
+}
+
+func TestReturnMutationsCompile(t *testing.T) {
+	mod := moduleWithSource(t, "returns", returnsSource)
+
+	for _, m := range applyAndBuild(t, mod, returnTypes) {
+		if m.Type() == mutator.ReturnError && !strings.Contains(m.Diff(), "+import \"errors\"") {
+			t.Errorf("expected the errors import to be added, got\n%s", m.Diff())
+		}
+	}
+}
@@ -0,0 +1,391 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"unicode"
+
+	"github.com/spf13/viper"
+
+	"github.com/singhnishant94/gremlins/internal/astutil"
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// Rule is a mutator declared in the configuration, which replaces the
+// expressions matching a pattern with a replacement:
+//
+//	unleash:
+//	  rules:
+//	    - name: HAS_SUFFIX
+//	      pattern: strings.HasPrefix(a, b)
+//	      replacement: strings.HasSuffix(a, b)
+//
+// As in the rewrite rules of gofmt -r, the identifiers made of a single
+// lowercase letter are wildcards, which match any expression. The same
+// wildcard must match the same expression everywhere in the pattern, and
+// in the replacement it stands for the expression it matched.
+type Rule struct {
+	mt          mutator.Type
+	pattern     ast.Expr
+	replacement ast.Expr
+}
+
+type ruleConfig struct {
+	Name        string `mapstructure:"name"`
+	Pattern     string `mapstructure:"pattern"`
+	Replacement string `mapstructure:"replacement"`
+}
+
+// ruleTypes are the mutator.Type registered for the rules, so that the
+// rules with the same name reuse them when read again.
+var ruleTypes = make(map[string]mutator.Type)
+
+// NewRules reads the rules of the configuration. Each rule is registered
+// as a new mutator.Type with its name, enabled unless disabled in the
+// configuration as the other mutator.Type.
+func NewRules() ([]Rule, error) {
+	// NOTE: configuration.Get can't type cast the list of rules, because
+	// viper.Get(k) returns []interface{}.
+	var cfgs []ruleConfig
+	if err := viper.UnmarshalKey(configuration.UnleashRulesKey, &cfgs); err != nil {
+		return nil, fmt.Errorf("error in rules: %w", err)
+	}
+
+	names := make(map[string]bool)
+	rules := make([]Rule, 0, len(cfgs))
+	for i, c := range cfgs {
+		if names[c.Name] {
+			return nil, fmt.Errorf("error in rule #%d: the name %q is already used", i, c.Name)
+		}
+		names[c.Name] = true
+		r, err := newRule(c)
+		if err != nil {
+			return nil, fmt.Errorf("error in rule #%d: %w", i, err)
+		}
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+func newRule(c ruleConfig) (Rule, error) {
+	pattern, err := parser.ParseExpr(c.Pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid pattern %q: %w", c.Pattern, err)
+	}
+	if isWildcard(pattern) {
+		return Rule{}, fmt.Errorf("the pattern %q matches any expression", c.Pattern)
+	}
+	replacement, err := parser.ParseExpr(c.Replacement)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid replacement %q: %w", c.Replacement, err)
+	}
+	wildcards := make(map[string]bool)
+	ast.Inspect(pattern, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && isWildcard(id) {
+			wildcards[id.Name] = true
+		}
+
+		return true
+	})
+	ast.Inspect(replacement, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && isWildcard(id) && !wildcards[id.Name] {
+			err = fmt.Errorf("the wildcard %q of the replacement is not in the pattern", id.Name)
+		}
+
+		return err == nil
+	})
+	if err != nil {
+		return Rule{}, err
+	}
+
+	mt, ok := ruleTypes[c.Name]
+	if !ok {
+		if err := mutator.CheckName(c.Name); err != nil {
+			return Rule{}, err
+		}
+		mt = mutator.Register(c.Name)
+		ruleTypes[c.Name] = mt
+	}
+	viper.SetDefault(configuration.MutantTypeEnabledKey(mt), true)
+
+	return Rule{mt: mt, pattern: pattern, replacement: replacement}, nil
+}
+
+// WithRules sets the rules whose mutants are searched in the files.
+func WithRules(rules []Rule) Option {
+	return func(m Engine) Engine {
+		m.rules = rules
+
+		return m
+	}
+}
+
+// findRuleMutations replaces the expressions of the file matching the
+// pattern of a rule. With the type information, the replacements which
+// drop the only use of a variable or of an import are skipped, as they
+// would not compile.
+func (mu *Engine) findRuleMutations(pkg string, set *token.FileSet, file *ast.File, info *types.Info, typed *typedFinder) {
+	for _, r := range mu.rules {
+		if !configuration.Get[bool](configuration.MutantTypeEnabledKey(r.mt)) {
+			continue
+		}
+		rule := r
+		ast.Inspect(file, func(node ast.Node) bool {
+			if node == nil {
+				return false
+			}
+			if stmt, ok := node.(ast.Stmt); ok && detectAridNodes && astutil.IsAridNode(stmt, info) {
+				return false
+			}
+			// The constants can't be replaced with any expression.
+			if decl, ok := node.(*ast.GenDecl); ok && decl.Tok == token.CONST {
+				return false
+			}
+			for _, expr := range childExprs(node) {
+				replacement, kept, ok := rule.apply(*expr)
+				if !ok || typed != nil && typed.replacesLastUses([]ast.Node{*expr}, kept) {
+					continue
+				}
+				m := NewASTMutator(pkg, set, file, *expr, replacement, replaceExpr(expr, replacement))
+				m.SetType(rule.mt)
+				m.SetStatus(mu.mutationStatus(set.Position((*expr).Pos())))
+
+				mu.mutants = append(mu.mutants, m)
+			}
+
+			return true
+		})
+	}
+}
+
+// apply returns the replacement of the expression if it matches the
+// pattern of the rule, and the nodes of the expression which are kept by
+// the replacement. These are the expressions matched by the wildcards of
+// the replacement and the identifiers it names, which refer to the same
+// objects in the file.
+func (r Rule) apply(expr ast.Expr) (ast.Expr, []ast.Node, bool) {
+	m := make(matches)
+	if !m.match(reflect.ValueOf(r.pattern), reflect.ValueOf(expr)) {
+		return nil, nil, false
+	}
+
+	var kept []ast.Node
+	names := make(map[string]bool)
+	ast.Inspect(r.replacement, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		switch {
+		case !ok:
+		case isWildcard(id):
+			kept = append(kept, m[id.Name].Interface().(ast.Node))
+		default:
+			names[id.Name] = true
+		}
+
+		return true
+	})
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && names[id.Name] {
+			kept = append(kept, id)
+		}
+
+		return true
+	})
+
+	return m.subst(reflect.ValueOf(r.replacement)).Interface().(ast.Expr), kept, true
+}
+
+// matches are the expressions matched by the wildcards of a pattern.
+type matches map[string]reflect.Value
+
+var (
+	identType  = reflect.TypeOf((*ast.Ident)(nil))
+	objectType = reflect.TypeOf((*ast.Object)(nil))
+	scopeType  = reflect.TypeOf((*ast.Scope)(nil))
+	posType    = reflect.TypeOf(token.NoPos)
+)
+
+// match checks if the value matches the pattern, ignoring the positions
+// and the objects of the identifiers. When m is nil, the wildcards only
+// match identical identifiers.
+func (m matches) match(pattern, val reflect.Value) bool {
+	if m != nil && pattern.IsValid() && pattern.Type() == identType {
+		if id := pattern.Interface().(*ast.Ident); isWildcard(id) && val.IsValid() {
+			if _, ok := val.Interface().(ast.Expr); ok && !val.IsNil() {
+				if old, ok := m[id.Name]; ok {
+					return matches(nil).match(old, val)
+				}
+				m[id.Name] = val
+
+				return true
+			}
+		}
+	}
+	if !pattern.IsValid() || !val.IsValid() {
+		return !pattern.IsValid() && !val.IsValid()
+	}
+	if pattern.Kind() == reflect.Interface {
+		return pattern.IsNil() == val.IsNil() && (pattern.IsNil() || m.match(pattern.Elem(), val.Elem()))
+	}
+	if pattern.Type() != val.Type() {
+		return false
+	}
+
+	switch pattern.Type() {
+	case identType:
+		p, v := pattern.Interface().(*ast.Ident), val.Interface().(*ast.Ident)
+
+		return p == nil && v == nil || p != nil && v != nil && p.Name == v.Name
+	case objectType, scopeType, posType:
+		return true
+	}
+
+	switch pattern.Kind() {
+	case reflect.Slice:
+		if pattern.Len() != val.Len() {
+			return false
+		}
+		for i := 0; i < pattern.Len(); i++ {
+			if !m.match(pattern.Index(i), val.Index(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Struct:
+		for i := 0; i < pattern.NumField(); i++ {
+			if !m.match(pattern.Field(i), val.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Pointer:
+		if pattern.IsNil() || val.IsNil() {
+			return pattern.IsNil() && val.IsNil()
+		}
+
+		return m.match(pattern.Elem(), val.Elem())
+	default:
+		return pattern.Interface() == val.Interface()
+	}
+}
+
+// subst returns a copy of the pattern in which the wildcards are replaced
+// with the expressions they matched, without the positions of the pattern.
+func (m matches) subst(pattern reflect.Value) reflect.Value {
+	if !pattern.IsValid() {
+		return reflect.Value{}
+	}
+	if pattern.Type() == identType {
+		id := pattern.Interface().(*ast.Ident)
+		if old, ok := m[id.Name]; ok && isWildcard(id) {
+			return old
+		}
+	}
+
+	switch pattern.Type() {
+	case posType:
+		return reflect.Zero(posType)
+	case objectType, scopeType:
+		return reflect.Zero(pattern.Type())
+	}
+
+	switch pattern.Kind() {
+	case reflect.Slice:
+		if pattern.IsNil() {
+			return pattern
+		}
+		v := reflect.MakeSlice(pattern.Type(), pattern.Len(), pattern.Len())
+		for i := 0; i < pattern.Len(); i++ {
+			v.Index(i).Set(m.subst(pattern.Index(i)))
+		}
+
+		return v
+	case reflect.Struct:
+		v := reflect.New(pattern.Type()).Elem()
+		for i := 0; i < pattern.NumField(); i++ {
+			if s := m.subst(pattern.Field(i)); s.IsValid() {
+				v.Field(i).Set(s)
+			}
+		}
+
+		return v
+	case reflect.Pointer:
+		if pattern.IsNil() {
+			return pattern
+		}
+		v := reflect.New(pattern.Type().Elem())
+		v.Elem().Set(m.subst(pattern.Elem()))
+
+		return v
+	case reflect.Interface:
+		if pattern.IsNil() {
+			return pattern
+		}
+		v := reflect.New(pattern.Type()).Elem()
+		v.Set(m.subst(pattern.Elem()))
+
+		return v
+	default:
+		return pattern
+	}
+}
+
+func isWildcard(expr ast.Expr) bool {
+	id, ok := expr.(*ast.Ident)
+
+	return ok && len(id.Name) == 1 && unicode.IsLower(rune(id.Name[0]))
+}
+
+var (
+	exprType      = reflect.TypeOf((*ast.Expr)(nil)).Elem()
+	exprSliceType = reflect.TypeOf([]ast.Expr(nil))
+)
+
+// childExprs returns the references to the expressions which are direct
+// children of the node, so that they can be replaced.
+func childExprs(node ast.Node) []*ast.Expr {
+	v := reflect.ValueOf(node)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	var res []*ast.Expr
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		switch f.Type() {
+		case exprType:
+			if !f.IsNil() {
+				res = append(res, f.Addr().Interface().(*ast.Expr))
+			}
+		case exprSliceType:
+			for j := 0; j < f.Len(); j++ {
+				res = append(res, f.Index(j).Addr().Interface().(*ast.Expr))
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const rulesSource = `package rules
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrNotFound = errors.New("not found")
+
+const Prefix = "v"
+
+func Versioned(s string) bool {
+	return strings.HasPrefix(s, Prefix)
+}
+
+func NotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+func Found(errs map[string]error, k string) bool {
+	err := errs[k]
+	return !errors.Is(err, ErrNotFound)
+}
+
+func Double(a, b int) int {
+	return a + a + (b + b)
+}
+`
+
+func rule(name, pattern, replacement string) map[string]any {
+	return map[string]any{"name": name, "pattern": pattern, "replacement": replacement}
+}
+
+var rulesConfig = []any{
+	rule("HAS_SUFFIX", "strings.HasPrefix(a, b)", "strings.HasSuffix(a, b)"),
+	rule("NOT_IS", "errors.Is(x, y)", "false"),
+	rule("DOUBLE", "a + a", "2 * a"),
+}
+
+func ruleTypes() map[mutator.Type]bool {
+	types := make(map[mutator.Type]bool)
+	for _, name := range []string{"HAS_SUFFIX", "NOT_IS", "DOUBLE"} {
+		if mt, ok := mutator.Lookup(name); ok {
+			types[mt] = true
+		}
+	}
+
+	return types
+}
+
+func TestRuleMutations(t *testing.T) {
+	testCases := []struct {
+		name     string
+		set      map[string]any
+		withPkgs bool
+		want     []string
+	}{
+		{
+			name:     "with type information",
+			set:      map[string]any{},
+			withPkgs: true,
+			// The errors.Is at line 22 is not replaced, as it is the only
+			// use of the err variable.
+			want: []string{
+				"13 HAS_SUFFIX strings.HasSuffix(s, Prefix)",
+				"17 NOT_IS false",
+				"26 DOUBLE 2 * a",
+				"26 DOUBLE 2 * b",
+			},
+		},
+		{
+			name: "without type information",
+			set:  map[string]any{},
+			want: []string{
+				"13 HAS_SUFFIX strings.HasSuffix(s, Prefix)",
+				"17 NOT_IS false",
+				"22 NOT_IS false",
+				"26 DOUBLE 2 * a",
+				"26 DOUBLE 2 * b",
+			},
+		},
+		{
+			name: "disabled in the configuration",
+			set: map[string]any{
+				"mutants.not-is.enabled": false,
+				"mutants.double.enabled": false,
+			},
+			want: []string{
+				"13 HAS_SUFFIX strings.HasSuffix(s, Prefix)",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mod := moduleWithSource(t, "rules", rulesSource)
+			set := map[string]any{
+				configuration.UnleashDryRunKey: true,
+				configuration.UnleashRulesKey:  rulesConfig,
+			}
+			for k, v := range tc.set {
+				set[k] = v
+			}
+			viperSet(set)
+			defer viperReset()
+
+			rules, err := engine.NewRules()
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := []engine.Option{engine.WithRules(rules)}
+			if tc.withPkgs {
+				pkgs, err := engine.LoadPackages(mod)
+				if err != nil {
+					t.Fatal(err)
+				}
+				opts = append(opts, engine.WithPackages(pkgs))
+			}
+			mut := engine.New(mod, engine.CodeData{}, newJobDealerStub(t), opts...)
+			res := mut.Run(context.Background())
+
+			got := describeMutants(res.Mutants, ruleTypes())
+			if !cmp.Equal(got, tc.want) {
+				t.Error(cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestRuleMutationsCompile(t *testing.T) {
+	mod := moduleWithSource(t, "rules", rulesSource)
+	viperSet(map[string]any{configuration.UnleashRulesKey: rulesConfig})
+	rules, err := engine.NewRules()
+	viperReset()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := applyAndBuild(t, mod, ruleTypes(), engine.WithRules(rules)); len(got) == 0 {
+		t.Error("expected the rules to be applied")
+	}
+}
+
+func TestNewRulesErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		rule map[string]any
+	}{
+		{
+			name: "invalid pattern",
+			rule: rule("INVALID_PATTERN", "strings.HasPrefix(", "true"),
+		},
+		{
+			name: "invalid replacement",
+			rule: rule("INVALID_REPLACEMENT", "strings.HasPrefix(a, b)", "a +"),
+		},
+		{
+			name: "wildcard pattern",
+			rule: rule("WILDCARD_PATTERN", "x", "nil"),
+		},
+		{
+			name: "unknown wildcard",
+			rule: rule("UNKNOWN_WILDCARD", "strings.HasPrefix(a, b)", "strings.HasPrefix(a, c)"),
+		},
+		{
+			name: "invalid name",
+			rule: rule("has-suffix", "strings.HasPrefix(a, b)", "strings.HasSuffix(a, b)"),
+		},
+		{
+			name: "builtin name",
+			rule: rule("ARITHMETIC_BASE", "a + b", "a - b"),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			viperSet(map[string]any{configuration.UnleashRulesKey: []any{tc.rule}})
+			defer viperReset()
+
+			if _, err := engine.NewRules(); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+
+	t.Run("duplicate name", func(t *testing.T) {
+		viperSet(map[string]any{configuration.UnleashRulesKey: []any{
+			rule("DUPLICATE", "a + b", "a - b"),
+			rule("DUPLICATE", "a - b", "a + b"),
+		}})
+		defer viperReset()
+
+		if _, err := engine.NewRules(); err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
@@ -0,0 +1,615 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/singhnishant94/gremlins/internal/engine/workdir"
+	"github.com/singhnishant94/gremlins/internal/engine/workerpool"
+	"github.com/singhnishant94/gremlins/internal/gomodule"
+	"github.com/singhnishant94/gremlins/internal/log"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const (
+	// schemataEnv is the environment variable holding the id of the mutant
+	// activated in the test binary of the mutant schemata.
+	schemataEnv = "GREMLINS_MUTANT"
+
+	// schemataVar is the variable added to the schematised packages, which
+	// holds the id of the active mutant.
+	schemataVar = "gremlinsSchemataMutant"
+
+	schemataFile = "gremlins_schemata.go"
+)
+
+// SchemataExecutorDealer is an ExecutorDealer which compiles the mutants of
+// each package once, in mutant schemata, instead of building the tests for
+// each mutant.
+//
+// For every mutant, the function containing it is copied with the mutation
+// applied, and the original function calls the copy when the mutant is the
+// active one. The instrumented files are passed to go test -c with the
+// -overlay flag, and the test binary is run once per mutant, activating it
+// through the GREMLINS_MUTANT environment variable.
+//
+// The mutants which can't be schematised, like the ones outside a function
+// or whose copy doesn't compile, are run one by one by a
+// MutantExecutorDealer.
+type SchemataExecutorDealer struct {
+	fallback *MutantExecutorDealer
+	schemas  map[mutator.Mutator]*schema
+}
+
+// NewSchemataExecutorDealer initialises a SchemataExecutorDealer. The
+// options are the ones of the MutantExecutorDealer running the mutants
+// which can't be schematised.
+func NewSchemataExecutorDealer(mod gomodule.GoModule, wdd workdir.Dealer, elapsed time.Duration, opts ...ExecutorDealerOption) *SchemataExecutorDealer {
+	return &SchemataExecutorDealer{
+		fallback: NewExecutorDealer(mod, wdd, elapsed, opts...),
+		schemas:  make(map[mutator.Mutator]*schema),
+	}
+}
+
+// Prepare groups by package the RUNNABLE mutants to schematise. The mutants
+// run with the race detector or not covered by any test of their package
+// are left out, as well as all the mutants in dry-run and in integration
+// mode. The mutated files are first rendered as by MutantExecutorDealer.
+func (d *SchemataExecutorDealer) Prepare(mutants []mutator.Mutator) {
+	d.fallback.Prepare(mutants)
+	if d.fallback.dryRun || d.fallback.integrationMode {
+		return
+	}
+	byPkg := make(map[string]*schema)
+	for _, m := range mutants {
+		if _, ok := m.(overlayMutator); !ok || m.Status() != mutator.Runnable || raceTypes[m.Type()] {
+			continue
+		}
+		if tests, ok := coveringTests(d.fallback.testsProfile, m, false); ok && len(tests) == 0 {
+			continue
+		}
+		s, ok := byPkg[m.Pkg()]
+		if !ok {
+			s = &schema{dealer: d.fallback, pkg: m.Pkg()}
+			byPkg[m.Pkg()] = s
+		}
+		s.mutants = append(s.mutants, m)
+		d.schemas[m] = s
+	}
+}
+
+// NewExecutor returns a new workerpool.Executor for the given mutator.Mutator,
+// which runs it in the test binary of its package if it is schematised.
+func (d *SchemataExecutorDealer) NewExecutor(mut mutator.Mutator, outCh chan<- mutator.Mutator, wg *sync.WaitGroup) workerpool.Executor {
+	fallback := d.fallback.newMutantExecutor(mut, outCh, wg)
+	s, ok := d.schemas[mut]
+	if !ok {
+		return fallback
+	}
+
+	return &schemataExecutor{schema: s, mutantExecutor: fallback}
+}
+
+type schemataExecutor struct {
+	*mutantExecutor
+	schema *schema
+}
+
+// Start builds the test binary of the package of the mutant, if it is the
+// first of its package, and runs it with the mutant active. The timeout is
+// measured on the run time of the binary without active mutants. If the
+// mutant is not in the binary, or the binary gives no result for it, it is
+// run as by the MutantExecutorDealer.
+func (e *schemataExecutor) Start(w *workerpool.Worker) {
+	status, ok := e.runSchema()
+	e.schema.release()
+	if !ok {
+		e.mutantExecutor.Start(w)
+
+		return
+	}
+	defer e.wg.Done()
+
+	e.mutant.SetStatus(status)
+	e.filterEquivalent("")
+
+	e.outCh <- e.mutant
+}
+
+// runSchema runs the test binary of the schema with the mutant active. It
+// returns false if the mutant is not in the binary, or if the binary gives
+// no result for it.
+func (e *schemataExecutor) runSchema() (mutator.Status, bool) {
+	binary, id, ok := e.schema.lookup(e.mutant)
+	if !ok {
+		return mutator.Runnable, false
+	}
+
+	e.mutant.SetWorkdir(filepath.Join(e.module.Root, e.module.CallingDir))
+	timeout := e.timeout()
+	if t, ok := e.packageTimeout(); ok {
+		timeout = t
+	}
+	if e.schema.elapsed != 0 {
+		timeout = e.calibrate(e.schema.elapsed)
+	}
+
+	return e.runBinary(binary, e.schema.dir, timeout, fmt.Sprintf("%s=%s", schemataEnv, id))
+}
+
+// schema is the mutant schemata of a package. The scratch folder, holding
+// the instrumented files and the test binary, is removed when the last of
+// its mutants is done.
+type schema struct {
+	once     sync.Once
+	mu       sync.Mutex
+	dealer   *MutantExecutorDealer
+	pkg      string
+	dir      string
+	scratch  string
+	mutants  []mutator.Mutator
+	binary   string
+	elapsed  time.Duration
+	ids      map[mutator.Mutator]string
+	finished int
+}
+
+// lookup returns the test binary of the package and the id of the mutant in
+// it, building it the first time. It returns false if the mutant is not in
+// the binary.
+func (s *schema) lookup(m mutator.Mutator) (string, string, bool) {
+	s.once.Do(s.build)
+	id, ok := s.ids[m]
+
+	return s.binary, id, ok && s.binary != ""
+}
+
+// release marks a mutant of the schema as done, and removes the scratch
+// folder after the last one.
+func (s *schema) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finished++
+	if s.finished == len(s.mutants) && s.scratch != "" {
+		removeScratch(s.scratch)
+	}
+}
+
+// schemaFile is a source file of the package, with the mutated copies of
+// its functions.
+type schemaFile struct {
+	path   string
+	src    []byte
+	set    *token.FileSet
+	file   *ast.File
+	copies []*schemaCopy
+}
+
+// schemaCopy is the copy of a function with a mutant applied. The from and
+// to lines are the ones of the copy in the instrumented file, and line is
+// the one of the opening brace of the function, where the copy is called.
+type schemaCopy struct {
+	mutant   mutator.Mutator
+	id       string
+	decl     *ast.FuncDecl
+	lbrace   int
+	line     int
+	text     string
+	from, to int
+	disabled bool
+}
+
+// build compiles the test binary of the package with all the mutants which
+// can be schematised. When the copies of some mutants don't compile, the
+// binary is built again without them.
+func (s *schema) build() {
+	root, err := filepath.Abs(filepath.Join(s.dealer.mod.Root, s.dealer.mod.CallingDir))
+	if err != nil {
+		log.Errorf("impossible to build the mutant schemata of %s: %s\n", s.pkg, err)
+
+		return
+	}
+	s.dir = filepath.Join(root, filepath.Dir(s.mutants[0].Position().Filename))
+	scratch, err := os.MkdirTemp(s.dealer.wdDealer.WorkDir(), "schemata-*")
+	if err != nil {
+		log.Errorf("impossible to build the mutant schemata of %s: %s\n", s.pkg, err)
+
+		return
+	}
+	scratch, _ = filepath.Abs(scratch)
+	s.scratch = scratch
+	defer func() {
+		// Without the binary, nothing is left to run in the scratch folder.
+		if s.binary == "" {
+			removeScratch(scratch)
+			s.scratch = ""
+		}
+	}()
+
+	files, pkgName := s.instrument(root, scratch)
+	for {
+		ids := make(map[mutator.Mutator]string)
+		for _, f := range files {
+			for _, c := range f.copies {
+				if !c.disabled {
+					ids[c.mutant] = c.id
+				}
+			}
+		}
+		if len(ids) == 0 {
+			return
+		}
+		overlay, err := writeSchemata(scratch, s.dir, pkgName, files)
+		if err != nil {
+			log.Errorf("impossible to build the mutant schemata of %s: %s\n", s.pkg, err)
+
+			return
+		}
+		binary := filepath.Join(scratch, "pkg.test")
+		out, err := s.compile(root, overlay, binary)
+		if err == nil {
+			if _, err := os.Stat(binary); err == nil {
+				s.binary, s.ids = binary, ids
+				s.elapsed = s.measure(binary)
+			}
+
+			return
+		}
+		if disableFailed(out, files) == 0 {
+			log.Errorf("impossible to build the mutant schemata of %s, its mutants are run one by one: %s\n%s", s.pkg, err, out)
+
+			return
+		}
+	}
+}
+
+// measure returns the time the test binary takes to run without active
+// mutants, or 0 if it fails.
+func (s *schema) measure(binary string) time.Duration {
+	ctx, cancel := context.WithTimeout(context.Background(), s.dealer.testExecutionTime)
+	defer cancel()
+	cmd := s.dealer.execContext(ctx, binary)
+	cmd.Dir = s.dir
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		return 0
+	}
+
+	return time.Since(start)
+}
+
+func (s *schema) compile(root, overlay, binary string) ([]byte, error) {
+	args := []string{"test", "-c", "-o", binary, "-overlay", overlay}
+	if s.dealer.buildTags != "" {
+		args = append(args, "-tags", s.dealer.buildTags)
+	}
+	args = append(args, s.pkg)
+	cmd := s.dealer.execContext(context.Background(), "go", args...)
+	cmd.Dir = root
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("GOTMPDIR=%s", s.dealer.wdDealer.WorkDir()))
+
+	return cmd.CombinedOutput()
+}
+
+// instrument makes the mutated copies of the functions of the mutants, and
+// returns the files containing them and the name of the package. The
+// mutants which can't be copied are left out.
+func (s *schema) instrument(root, scratch string) ([]*schemaFile, string) {
+	byFile := make(map[string]*schemaFile)
+	var files []*schemaFile
+	var pkgName string
+	for i, m := range s.mutants {
+		filename := m.Position().Filename
+		f, ok := byFile[filename]
+		if !ok {
+			var err error
+			f, err = parseSchemaFile(filepath.Join(root, filename))
+			if err != nil {
+				log.Errorf("impossible to read %s for the mutant schemata: %s\n", filename, err)
+			}
+			byFile[filename] = f
+			if f != nil {
+				files = append(files, f)
+			}
+		}
+		if f == nil {
+			continue
+		}
+		c, err := f.copyFunc(m, strconv.Itoa(i+1), scratch)
+		if err != nil {
+			continue
+		}
+		pkgName = f.file.Name.Name
+		f.copies = append(f.copies, c)
+	}
+
+	return files, pkgName
+}
+
+func parseSchemaFile(path string) (*schemaFile, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	set := token.NewFileSet()
+	file, err := parser.ParseFile(set, path, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	for _, is := range file.Imports {
+		if is.Path.Value == `"C"` {
+			return nil, errors.New("the cgo files can't be overlaid")
+		}
+	}
+
+	return &schemaFile{path: path, src: src, set: set, file: file}, nil
+}
+
+// copyFunc makes the copy of the function containing the mutant, with the
+// mutation applied. It fails if the mutant is not in a function which can
+// be copied and called with its own parameters.
+func (f *schemaFile) copyFunc(m mutator.Mutator, id, scratch string) (*schemaCopy, error) {
+	offset := m.Position().Offset
+	idx := -1
+	for i, d := range f.file.Decls {
+		if f.set.Position(d.Pos()).Offset <= offset && offset < f.set.Position(d.End()).Offset {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return nil, errors.New("the mutant is not in a declaration")
+	}
+	decl, ok := f.file.Decls[idx].(*ast.FuncDecl)
+	if !ok || !isCallable(decl) {
+		return nil, errors.New("the mutant is not in a function which can be copied")
+	}
+
+	mutatedPath := filepath.Join(scratch, "mutant-"+id+".go")
+	if err := m.(overlayMutator).ApplyTo(mutatedPath); err != nil {
+		return nil, err
+	}
+	mutated, err := os.ReadFile(mutatedPath)
+	_ = os.Remove(mutatedPath)
+	if err != nil {
+		return nil, err
+	}
+	mSet := token.NewFileSet()
+	mFile, err := parser.ParseFile(mSet, "", mutated, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	if len(mFile.Decls) != len(f.file.Decls) {
+		return nil, errors.New("the mutation changes the declarations")
+	}
+	mDecl, ok := mFile.Decls[idx].(*ast.FuncDecl)
+	if !ok || mDecl.Name.Name != decl.Name.Name {
+		return nil, errors.New("the mutation changes the declarations")
+	}
+
+	start := mSet.Position(mDecl.Pos()).Offset
+	name := mSet.Position(mDecl.Name.Pos()).Offset
+	end := mSet.Position(mDecl.End()).Offset
+	text := string(mutated[start:name]) + copyName(decl, id) + string(mutated[name+len(decl.Name.Name):end])
+
+	return &schemaCopy{
+		mutant: m,
+		id:     id,
+		decl:   decl,
+		lbrace: f.set.Position(decl.Body.Lbrace).Offset,
+		line:   f.set.Position(decl.Body.Lbrace).Line,
+		text:   text,
+	}, nil
+}
+
+// isCallable checks if the function can call its copy, which needs the
+// names of the receiver and of all the parameters.
+func isCallable(decl *ast.FuncDecl) bool {
+	if decl.Body == nil {
+		return false
+	}
+	named := func(fl *ast.FieldList) bool {
+		if fl == nil {
+			return true
+		}
+		for _, f := range fl.List {
+			if len(f.Names) == 0 {
+				return false
+			}
+			for _, n := range f.Names {
+				if n.Name == "_" {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+	if decl.Recv != nil && (len(decl.Recv.List) == 0 || !named(decl.Recv)) {
+		return false
+	}
+
+	return named(decl.Type.Params)
+}
+
+func copyName(decl *ast.FuncDecl, id string) string {
+	return "gremlinsSchemata" + id + decl.Name.Name
+}
+
+// callCopy returns the statements calling the copy of the function and
+// returning its results.
+func callCopy(decl *ast.FuncDecl, id string) string {
+	var call strings.Builder
+	if decl.Recv != nil {
+		call.WriteString(decl.Recv.List[0].Names[0].Name + ".")
+	}
+	call.WriteString(copyName(decl, id))
+	if tp := decl.Type.TypeParams; tp != nil {
+		var names []string
+		for _, f := range tp.List {
+			for _, n := range f.Names {
+				names = append(names, n.Name)
+			}
+		}
+		call.WriteString("[" + strings.Join(names, ", ") + "]")
+	}
+	var args []string
+	for _, f := range decl.Type.Params.List {
+		for _, n := range f.Names {
+			arg := n.Name
+			if _, ok := f.Type.(*ast.Ellipsis); ok {
+				arg += "..."
+			}
+			args = append(args, arg)
+		}
+	}
+	call.WriteString("(" + strings.Join(args, ", ") + ")")
+
+	if decl.Type.Results == nil {
+		return call.String() + "; return"
+	}
+
+	return "return " + call.String()
+}
+
+// instrumented returns the source of the file with the active copies
+// appended, and the original functions switching to them. The switches are
+// on the line of the opening brace of the functions, so that the lines of
+// the original code don't change.
+func (f *schemaFile) instrumented() []byte {
+	byBrace := make(map[int][]*schemaCopy)
+	var braces []int
+	for _, c := range f.copies {
+		if c.disabled {
+			continue
+		}
+		if _, ok := byBrace[c.lbrace]; !ok {
+			braces = append(braces, c.lbrace)
+		}
+		byBrace[c.lbrace] = append(byBrace[c.lbrace], c)
+	}
+	sort.Ints(braces)
+
+	b := &bytes.Buffer{}
+	prev := 0
+	for _, lbrace := range braces {
+		b.Write(f.src[prev : lbrace+1])
+		fmt.Fprintf(b, " switch %s {", schemataVar)
+		for _, c := range byBrace[lbrace] {
+			fmt.Fprintf(b, " case %q: %s;", c.id, callCopy(c.decl, c.id))
+		}
+		b.WriteString(" };")
+		prev = lbrace + 1
+	}
+	b.Write(f.src[prev:])
+
+	for _, lbrace := range braces {
+		for _, c := range byBrace[lbrace] {
+			b.WriteString("\n")
+			c.from = bytes.Count(b.Bytes(), []byte("\n")) + 1
+			b.WriteString(c.text)
+			c.to = c.from + strings.Count(c.text, "\n")
+			b.WriteString("\n")
+		}
+	}
+
+	return b.Bytes()
+}
+
+// writeSchemata writes the instrumented files, the file declaring the
+// variable of the active mutant and the overlay file replacing the sources
+// with them. It returns the path of the overlay file.
+func writeSchemata(scratch, dir, pkgName string, files []*schemaFile) (string, error) {
+	replace := make(map[string]string)
+	for i, f := range files {
+		// The instrumented files keep the name of the source, which appears
+		// in the errors of the compiler.
+		fDir := filepath.Join(scratch, strconv.Itoa(i))
+		if err := os.MkdirAll(fDir, 0700); err != nil {
+			return "", err
+		}
+		path := filepath.Join(fDir, filepath.Base(f.path))
+		if err := os.WriteFile(path, f.instrumented(), 0600); err != nil {
+			return "", err
+		}
+		replace[f.path] = path
+	}
+
+	decl := fmt.Sprintf("// Code generated by gremlins. DO NOT EDIT.\n\npackage %s\n\nimport \"os\"\n\nvar %s = os.Getenv(%q)\n",
+		pkgName, schemataVar, schemataEnv)
+	path := filepath.Join(scratch, schemataFile)
+	if err := os.WriteFile(path, []byte(decl), 0600); err != nil {
+		return "", err
+	}
+	replace[filepath.Join(dir, schemataFile)] = path
+
+	// This is the format expected by the -overlay flag of the go command.
+	overlay := struct {
+		Replace map[string]string
+	}{Replace: replace}
+	data, err := json.Marshal(overlay)
+	if err != nil {
+		return "", err
+	}
+	overlayFile := filepath.Join(scratch, "overlay.json")
+
+	return overlayFile, os.WriteFile(overlayFile, data, 0600)
+}
+
+var compileError = regexp.MustCompile(`(?m)^\S*?([^\s/\\]+\.go):(\d+)(?::\d+)?: `)
+
+// disableFailed disables the copies on which the compiler reports an error
+// and returns how many they are. An error on the line of the switch of a
+// function disables all the copies it calls, since they are all called on
+// that line.
+func disableFailed(out []byte, files []*schemaFile) int {
+	disabled := 0
+	for _, match := range compileError.FindAllSubmatch(out, -1) {
+		base := string(match[1])
+		line, _ := strconv.Atoi(string(match[2]))
+		for _, f := range files {
+			if filepath.Base(f.path) != base {
+				continue
+			}
+			for _, c := range f.copies {
+				if !c.disabled && (line >= c.from && line <= c.to || line == c.line) {
+					c.disabled = true
+					disabled++
+				}
+			}
+		}
+	}
+
+	return disabled
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/engine/workdir"
+	"github.com/singhnishant94/gremlins/internal/engine/workerpool"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const schemataSource = `package schema
+
+type Acc struct{ n int }
+
+func (a *Acc) Add(vs ...int) {
+	for _, v := range vs {
+		a.n += v
+	}
+}
+
+func Max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func Greet(name string) string {
+	return "hello " + name
+}
+
+func Next[_ any](n int) int {
+	return n + 1
+}
+`
+
+const schemataTestSource = `package schema
+
+import "testing"
+
+func TestAcc(t *testing.T) {
+	var a Acc
+	a.Add(1, 2)
+	if a.n != 3 {
+		t.Fatal(a.n)
+	}
+}
+
+func TestMax(t *testing.T) {
+	if Max(2, 1) != 2 || Max(1, 2) != 2 {
+		t.Fatal()
+	}
+}
+
+func TestGreet(t *testing.T) {
+	if Greet("x") != "hello x" {
+		t.Fatal()
+	}
+}
+
+func TestNext(t *testing.T) {
+	if Next[int](1) != 2 {
+		t.Fatal()
+	}
+}
+`
+
+func TestSchemataExecutorDealer(t *testing.T) {
+	mod := moduleWithSource(t, "schema", schemataSource)
+	if err := os.WriteFile(filepath.Join(mod.Root, "schema_test.go"), []byte(schemataTestSource), 0600); err != nil {
+		t.Fatal(err)
+	}
+	types := map[mutator.Type]bool{
+		mutator.ArithmeticBase:       true,
+		mutator.ConditionalsBoundary: true,
+		mutator.ConditionalsNegation: true,
+		mutator.InvertAssignments:    true,
+	}
+
+	// The mutants are found without the type information, so that the
+	// ARITHMETIC_BASE on the string concatenation is not filtered out. It
+	// doesn't compile, so it is run outside the schemata.
+	viperSet(map[string]any{configuration.UnleashDryRunKey: true})
+	mut := engine.New(mod, engine.CodeData{}, newJobDealerStub(t))
+	res := mut.Run(context.Background())
+	viperReset()
+	var mutants []mutator.Mutator
+	for _, m := range res.Mutants {
+		if types[m.Type()] {
+			m.SetStatus(mutator.Runnable)
+			mutants = append(mutants, m)
+		}
+	}
+
+	viperSet(map[string]any{})
+	defer viperReset()
+	wdd := workdir.NewCachedDealer(t.TempDir(), mod.Root)
+	defer wdd.Clean()
+	copies := 0
+	wdDealer := newWdDealerStub(t)
+	wdDealer.fnGet = func(idf string) (string, error) {
+		copies++
+
+		return wdd.Get(idf)
+	}
+	builds := 0
+	var binary string
+	var mu sync.Mutex
+	execContext := func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		if name == "go" && len(args) > 3 && args[1] == "-c" {
+			mu.Lock()
+			builds++
+			binary = args[3]
+			mu.Unlock()
+		}
+
+		return exec.CommandContext(ctx, name, args...)
+	}
+	dealer := engine.NewSchemataExecutorDealer(mod, wdDealer, expectedTimeout, engine.WithExecContext(execContext))
+	dealer.Prepare(mutants)
+
+	outCh := make(chan mutator.Mutator, len(mutants))
+	wg := &sync.WaitGroup{}
+	for _, m := range mutants {
+		wg.Add(1)
+		dealer.NewExecutor(m, outCh, wg).Start(&workerpool.Worker{Name: "test", ID: 1})
+	}
+	wg.Wait()
+	close(outCh)
+
+	var got []string
+	for m := range outCh {
+		// The status of the mutant which doesn't compile depends on the exit
+		// code of go test, as for the other executors.
+		if m.Type() == mutator.ArithmeticBase {
+			continue
+		}
+		got = append(got, fmt.Sprintf("%d %s %s", m.Position().Line, m.Type(), m.Status()))
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"12 CONDITIONALS_BOUNDARY LIVED",
+		"12 CONDITIONALS_NEGATION KILLED",
+		"7 INVERT_ASSIGNMENTS KILLED",
+	}
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+	// The first build fails on the copy of Greet and on the call of the copy
+	// of Next, whose type parameter can't be passed. They are left out of
+	// the second one and run in a copy of the module.
+	if builds != 2 {
+		t.Errorf("expected 2 builds, got %d", builds)
+	}
+	if copies != 2 {
+		t.Errorf("expected 2 copies of the module, got %d", copies)
+	}
+	if _, err := os.Stat(filepath.Dir(binary)); !os.IsNotExist(err) {
+		t.Errorf("expected the schemata folder to be removed, got %v", err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(mod.Root, "schema.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(src), "gremlins") {
+		t.Errorf("expected the source to be untouched, got:\n%s", src)
+	}
+}
@@ -63,12 +63,12 @@ func loadFixture(fixture, fromPackage string) (fstest.MapFS, gomodule.GoModule,
 	}
 
 	return mapFS, gomodule.GoModule{
-			Name:       "example.com",
-			Root:       ".",
-			CallingDir: fromPackage,
-		}, func() {
-			_ = f.Close()
-		}
+		Name:       "example.com",
+		Root:       ".",
+		CallingDir: fromPackage,
+	}, func() {
+		_ = f.Close()
+	}
 }
 
 func filenameFromFixture(fix string) string {
@@ -96,6 +96,8 @@ func (dealerStub) Clean() {}
 
 func (dealerStub) WorkDir() string { return "/tmp" }
 
+func (dealerStub) SrcDir() string { return "/tmp" }
+
 type executorDealerStub struct {
 	gotMutants []mutator.Mutator
 }
@@ -133,6 +135,8 @@ type mutantStub struct {
 	position       token.Position
 	status         mutator.Status
 	mutType        mutator.Type
+	diff           string
+	testExecErr    error
 	applyCalled    bool
 	rollbackCalled bool
 
@@ -189,3 +193,35 @@ func (m *mutantStub) Rollback() error {
 
 	return nil
 }
+
+func (m *mutantStub) Diff() string {
+	return m.diff
+}
+
+func (m *mutantStub) SetDiff(d string) {
+	m.diff = d
+}
+
+func (m *mutantStub) SetTestExecutionError(err error) {
+	m.testExecErr = err
+}
+
+func (m *mutantStub) TestExecutionError() error {
+	return m.testExecErr
+}
+
+// overlayMutantStub is a mutantStub which can write the mutated file
+// elsewhere than over the source one, as needed by the overlay mode.
+type overlayMutantStub struct {
+	mutantStub
+	appliedTo string
+}
+
+func (m *overlayMutantStub) ApplyTo(filename string) error {
+	m.appliedTo = filename
+	if m.hasApplyError {
+		return errors.New("test error")
+	}
+
+	return os.WriteFile(filename, []byte("package mutated\n"), 0600)
+}
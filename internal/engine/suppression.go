@@ -0,0 +1,149 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/singhnishant94/gremlins/internal/log"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// ignoreDirective is the comment which suppresses the mutants. It can be
+// followed by a comma separated list of mutator.Type, to suppress only the
+// mutants of those types:
+//
+//	//gremlins:ignore CONDITIONALS_BOUNDARY,REMOVE_STATEMENT
+const ignoreDirective = "//gremlins:ignore"
+
+// suppression is the range of lines covered by an ignore directive. The
+// types are nil if the directive suppresses all the mutants.
+type suppression struct {
+	from, to int
+	types    map[mutator.Type]bool
+}
+
+func (s suppression) covers(m mutator.Mutator) bool {
+	line := m.Position().Line
+
+	return line >= s.from && line <= s.to && (s.types == nil || s.types[m.Type()])
+}
+
+// ignoreSuppressed sets the status of the mutants of the file covered by
+// an ignore directive to mutator.Ignored.
+func ignoreSuppressed(set *token.FileSet, file *ast.File, mutants []mutator.Mutator) {
+	sups := suppressions(set, file)
+	if len(sups) == 0 {
+		return
+	}
+	for _, m := range mutants {
+		for _, s := range sups {
+			if s.covers(m) {
+				m.SetStatus(mutator.Ignored)
+
+				break
+			}
+		}
+	}
+}
+
+// suppressions returns the ranges of lines covered by the ignore directives
+// of the file. A directive at the end of a line covers that line. Otherwise,
+// it covers the code starting on the line below it, which is the whole
+// function or block if it is one.
+func suppressions(set *token.FileSet, file *ast.File) []suppression {
+	var directives []*ast.Comment
+	var below []int
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			if isIgnoreDirective(c.Text) {
+				directives = append(directives, c)
+				below = append(below, set.Position(cg.End()).Line+1)
+			}
+		}
+	}
+	if len(directives) == 0 {
+		return nil
+	}
+
+	// codeColumn is the first column of the code of each line, and ends is
+	// the last line of the outermost node starting on each line.
+	codeColumn := make(map[int]int)
+	ends := make(map[int]int)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil:
+			return false
+		case *ast.CommentGroup, *ast.Comment:
+			return false
+		}
+		start, end := set.Position(n.Pos()), set.Position(n.End())
+		for _, p := range []token.Position{start, end} {
+			if c, ok := codeColumn[p.Line]; !ok || p.Column < c {
+				codeColumn[p.Line] = p.Column
+			}
+		}
+		if end.Line > ends[start.Line] {
+			ends[start.Line] = end.Line
+		}
+
+		return true
+	})
+
+	sups := make([]suppression, 0, len(directives))
+	for i, c := range directives {
+		pos := set.Position(c.Pos())
+		s := suppression{from: below[i], to: below[i], types: directiveTypes(c.Text, pos)}
+		if col, ok := codeColumn[pos.Line]; ok && col < pos.Column {
+			s.from, s.to = pos.Line, pos.Line
+		} else if end, ok := ends[s.from]; ok {
+			s.to = end
+		}
+		sups = append(sups, s)
+	}
+
+	return sups
+}
+
+func isIgnoreDirective(text string) bool {
+	rest, ok := strings.CutPrefix(text, ignoreDirective)
+
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// directiveTypes returns the mutator.Type listed by the directive, or nil
+// if it doesn't list any. The unknown types are reported and skipped.
+func directiveTypes(text string, pos token.Position) map[mutator.Type]bool {
+	fields := strings.Fields(strings.TrimPrefix(text, ignoreDirective))
+	if len(fields) == 0 {
+		return nil
+	}
+	types := make(map[mutator.Type]bool)
+	for _, name := range strings.Split(fields[0], ",") {
+		mt, ok := mutator.Lookup(name)
+		if !ok {
+			log.Errorf("unknown mutant type %q in the ignore directive at %s\n", name, pos)
+
+			continue
+		}
+		types[mt] = true
+	}
+
+	return types
+}
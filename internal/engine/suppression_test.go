@@ -0,0 +1,119 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const suppressionSource = `package suppressed
+
+func Line(a, b int) bool {
+	c := a > b //gremlins:ignore
+	d := a < b
+
+	return c && d
+}
+
+//gremlins:ignore
+func Func(a, b int) bool {
+	return a > b
+}
+
+// Boundary is only ignored for some types.
+//
+//gremlins:ignore CONDITIONALS_BOUNDARY,REMOVE_STATEMENT
+func Boundary(a, b int) bool {
+	return a > b
+}
+
+func Block(a, b int) int {
+	//gremlins:ignore
+	if a > b {
+		a--
+	}
+	a++
+
+	return a
+}
+
+func Unknown(a, b int) bool {
+	return a > b //gremlins:ignore NOT_A_TYPE
+}
+
+func NotDirective(a, b int) bool {
+	return a > b //gremlins:ignored
+}
+`
+
+func TestSuppressedMutants(t *testing.T) {
+	mod := moduleWithSource(t, "suppressed", suppressionSource)
+	viperSet(map[string]any{
+		configuration.UnleashDryRunKey:                                   true,
+		configuration.MutantTypeEnabledKey(mutator.ConditionalsBoundary): true,
+		configuration.MutantTypeEnabledKey(mutator.ConditionalsNegation): true,
+		configuration.MutantTypeEnabledKey(mutator.IncrementDecrement):   true,
+	})
+	defer viperReset()
+
+	mut := engine.New(mod, engine.CodeData{}, newJobDealerStub(t))
+	res := mut.Run(context.Background())
+
+	types := map[mutator.Type]bool{
+		mutator.ConditionalsBoundary: true,
+		mutator.ConditionalsNegation: true,
+		mutator.IncrementDecrement:   true,
+	}
+	var got []string
+	for _, m := range res.Mutants {
+		if types[m.Type()] {
+			got = append(got, fmt.Sprintf("%d %s %s", m.Position().Line, m.Type(), m.Status()))
+		}
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"12 CONDITIONALS_BOUNDARY IGNORED",
+		"12 CONDITIONALS_NEGATION IGNORED",
+		"19 CONDITIONALS_BOUNDARY IGNORED",
+		"19 CONDITIONALS_NEGATION NOT COVERED",
+		"24 CONDITIONALS_BOUNDARY IGNORED",
+		"24 CONDITIONALS_NEGATION IGNORED",
+		"25 INCREMENT_DECREMENT IGNORED",
+		"27 INCREMENT_DECREMENT NOT COVERED",
+		"33 CONDITIONALS_BOUNDARY NOT COVERED",
+		"33 CONDITIONALS_NEGATION NOT COVERED",
+		"37 CONDITIONALS_BOUNDARY NOT COVERED",
+		"37 CONDITIONALS_NEGATION NOT COVERED",
+		"4 CONDITIONALS_BOUNDARY IGNORED",
+		"4 CONDITIONALS_NEGATION IGNORED",
+		"5 CONDITIONALS_BOUNDARY NOT COVERED",
+		"5 CONDITIONALS_NEGATION NOT COVERED",
+	}
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
@@ -17,16 +17,10 @@
 package engine
 
 import (
-	"bytes"
-	"fmt"
 	"go/ast"
-	"go/printer"
 	"go/token"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"sync"
-	"syscall"
 
 	"github.com/singhnishant94/gremlins/internal/mutator"
 )
@@ -47,10 +41,10 @@ type TokenMutator struct {
 	file        *ast.File
 	tokenNode   *NodeToken
 	workDir     string
+	rendered    *renderedSource
 	origFile    []byte
 	status      mutator.Status
 	mutantType  mutator.Type
-	actualToken token.Token
 	diff        string
 	testExecErr error
 }
@@ -105,6 +99,35 @@ func (m *TokenMutator) SetDiff(d string) {
 	m.diff = d
 }
 
+// Replacement returns the code which replaces the original one.
+func (m *TokenMutator) Replacement() string {
+	switch m.Type() {
+	case mutator.RemoveBinaryExpressionLeft, mutator.RemoveBinaryExpressionRight:
+		if m.tokenNode.Tok() == token.LOR {
+			return "false"
+		}
+
+		return "true"
+	default:
+		return tokenMutations[m.Type()][m.tokenNode.Tok()].String()
+	}
+}
+
+// Location returns the start and end token.Position of the replaced code.
+func (m *TokenMutator) Location() (token.Position, token.Position) {
+	if n, ok := (*m.tokenNode.node).(*ast.BinaryExpr); ok {
+		switch m.Type() {
+		case mutator.RemoveBinaryExpressionLeft:
+			return m.fs.Position(n.X.Pos()), m.fs.Position(n.X.End())
+		case mutator.RemoveBinaryExpressionRight:
+			return m.fs.Position(n.Y.Pos()), m.fs.Position(n.Y.End())
+		}
+	}
+	end := m.tokenNode.TokPos + token.Pos(len(m.tokenNode.Tok().String()))
+
+	return m.Position(), m.fs.Position(end)
+}
+
 // Pkg returns the package name to which the mutant belongs.
 func (m *TokenMutator) Pkg() string {
 	return m.pkg
@@ -120,9 +143,6 @@ func (m *TokenMutator) Pkg() string {
 // This is done in order to facilitate the atomicity of the operation,
 // avoiding locking in a method and unlocking in another.
 func (m *TokenMutator) Apply() error {
-	fileLock(m.Position().Filename).Lock()
-	defer fileLock(m.Position().Filename).Unlock()
-
 	filename := filepath.Join(m.workDir, m.Position().Filename)
 	var err error
 	m.origFile, err = os.ReadFile(filename)
@@ -130,24 +150,40 @@ func (m *TokenMutator) Apply() error {
 		return err
 	}
 
-	// Create a copy of the original file to calculate the diff later.
-	copyOrigFileName := filepath.Join(m.workDir, m.Position().Filename+".copy.orig")
-	if err = m.writeMutatedFile(copyOrigFileName); err != nil {
+	return m.ApplyTo(filename)
+}
+
+// ApplyTo writes the mutated file to the given path and sets the diff,
+// without reading the source file. When the path is not the one of the
+// source file, the source is left untouched and there is nothing to roll
+// back. If the mutant has been prerendered, as in overlay mode, the file
+// lock is not taken.
+func (m *TokenMutator) ApplyTo(filename string) error {
+	diff, err := applyMutant(filename, m.rendered, m.fs, m.file, m.mutate)
+	if err != nil {
 		return err
 	}
+	m.SetDiff(diff)
 
-	var oldX ast.Expr
-	var oldY ast.Expr
+	return nil
+}
 
+// prerender renders the mutated source ahead of the run, so that ApplyTo
+// doesn't need to lock the file.
+func (m *TokenMutator) prerender(rs renderedSources) {
+	m.rendered = rs.render(m.fs, m.file, m.mutate)
+}
+
+// mutate sets the token from the tokenMutations table, or replaces the
+// removed side of the binary expression, and returns the function which
+// puts back the original.
+func (m *TokenMutator) mutate() func() {
 	switch m.Type() {
-	case mutator.RemoveBinaryExpressionLeft:
+	case mutator.RemoveBinaryExpressionLeft, mutator.RemoveBinaryExpressionRight:
 		n, ok := (*m.tokenNode.node).(*ast.BinaryExpr)
 		if !ok {
 			return nil
 		}
-		if n.Op != token.LAND && n.Op != token.LOR {
-			return nil
-		}
 
 		var r *ast.Ident
 
@@ -156,138 +192,26 @@ func (m *TokenMutator) Apply() error {
 			r = ast.NewIdent("true")
 		case token.LOR:
 			r = ast.NewIdent("false")
-		}
-
-		oldX = n.X
-
-		n.X = r
-	case mutator.RemoveBinaryExpressionRight:
-		n, ok := (*m.tokenNode.node).(*ast.BinaryExpr)
-		if !ok {
-			return nil
-		}
-		if n.Op != token.LAND && n.Op != token.LOR {
+		default:
 			return nil
 		}
 
-		var r *ast.Ident
+		if m.Type() == mutator.RemoveBinaryExpressionLeft {
+			oldX := n.X
+			n.X = r
 
-		switch n.Op {
-		case token.LAND:
-			r = ast.NewIdent("true")
-		case token.LOR:
-			r = ast.NewIdent("false")
+			return func() { n.X = oldX }
 		}
-
-		oldY = n.Y
-
+		oldY := n.Y
 		n.Y = r
-	default:
-		m.actualToken = m.tokenNode.Tok()
-		m.tokenNode.SetTok(tokenMutations[m.Type()][m.tokenNode.Tok()])
-	}
-
-	if err = m.writeMutatedFile(filename); err != nil {
-		return err
-	}
-
-	// Rollback here to facilitate the atomicity of the operation.
-	switch m.Type() {
-	case mutator.RemoveBinaryExpressionLeft:
-		n, ok := (*m.tokenNode.node).(*ast.BinaryExpr)
-		if !ok {
-			return nil
-		}
-		if n.Op != token.LAND && n.Op != token.LOR {
-			return nil
-		}
-
-		n.X = oldX
 
-	case mutator.RemoveBinaryExpressionRight:
-		n, ok := (*m.tokenNode.node).(*ast.BinaryExpr)
-		if !ok {
-			return nil
-		}
-		if n.Op != token.LAND && n.Op != token.LOR {
-			return nil
-		}
-
-		n.Y = oldY
+		return func() { n.Y = oldY }
 	default:
-		m.tokenNode.SetTok(m.actualToken)
-	}
-
-	m.SetDiff(m.calcDiff(copyOrigFileName, filename))
+		actualToken := m.tokenNode.Tok()
+		m.tokenNode.SetTok(tokenMutations[m.Type()][actualToken])
 
-	// Remove the copy of the original file.
-	os.Remove(copyOrigFileName)
-
-	return nil
-}
-
-func (m *TokenMutator) writeMutatedFile(filename string) error {
-	w := &bytes.Buffer{}
-	err := printer.Fprint(w, m.fs, m.file)
-	if err != nil {
-		return err
+		return func() { m.tokenNode.SetTok(actualToken) }
 	}
-
-	err = os.WriteFile(filename, w.Bytes(), 0600)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (m *TokenMutator) calcDiff(origFile, mutationFile string) string {
-	diff, err := exec.Command("diff", "--label=Original", "--label=New", "-u", origFile, mutationFile).CombinedOutput()
-	var execExitCode int
-	if err == nil {
-		execExitCode = 0
-	} else if e, ok := err.(*exec.ExitError); ok {
-		execExitCode = e.Sys().(syscall.WaitStatus).ExitStatus()
-	} else {
-		panic(err)
-	}
-	if execExitCode != 0 && execExitCode != 1 {
-		fmt.Printf("%s\n", diff)
-
-		panic("Could not execute diff on mutation file")
-	}
-
-	return string(diff)
-}
-
-var locks = make(map[string]*sync.Mutex)
-var mutex sync.RWMutex
-
-func fileLock(filename string) *sync.Mutex {
-	lock, ok := cachedLock(filename)
-	if !ok {
-		mutex.Lock()
-		defer mutex.Unlock()
-		lock, ok = locks[filename]
-		if !ok {
-			lock = &sync.Mutex{}
-			locks[filename] = lock
-
-			return lock
-		}
-
-		return lock
-	}
-
-	return lock
-}
-
-func cachedLock(filename string) (*sync.Mutex, bool) {
-	mutex.RLock()
-	defer mutex.RUnlock()
-	lock, ok := locks[filename]
-
-	return lock, ok
 }
 
 // Rollback puts back the original file after the test and cleans up the
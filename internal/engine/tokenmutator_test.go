@@ -22,11 +22,14 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/singhnishant94/gremlins/internal/configuration"
 	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/gomodule"
 	"github.com/singhnishant94/gremlins/internal/mutator"
 )
 
@@ -97,3 +100,187 @@ func TestMutantApplyAndRollback(t *testing.T) {
 		}
 	}
 }
+
+func TestMutantApplyTo(t *testing.T) {
+	src := "package main\n\nfunc main() {\n\ta := 1 + 2\n}\n"
+	want := "package main\n\nfunc main() {\n\ta := 1 - 2\n}\n"
+
+	workdir := t.TempDir()
+	fileFullPath := filepath.Join(workdir, "sourceFile.go")
+	if err := os.WriteFile(fileFullPath, []byte(src), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	set := token.NewFileSet()
+	f, err := parser.ParseFile(set, "sourceFile.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var node *ast.BinaryExpr
+	ast.Inspect(f, func(n ast.Node) bool {
+		if n, ok := n.(*ast.BinaryExpr); ok {
+			node = n
+		}
+
+		return true
+	})
+	n, ok := engine.NewTokenNode(node)
+	if !ok {
+		t.Fatal("new actualToken node should be created")
+	}
+	mut := engine.NewTokenMutant("example.com/test", set, f, n)
+	mut.SetType(mutator.ArithmeticBase)
+	mut.SetStatus(mutator.Runnable)
+	mut.SetWorkdir(workdir)
+
+	mutatedPath := filepath.Join(t.TempDir(), "sourceFile.go")
+	if err = mut.ApplyTo(mutatedPath); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(mutatedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(string(got), want) {
+		t.Error(cmp.Diff(want, string(got)))
+	}
+	got, err = os.ReadFile(fileFullPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(string(got), src) {
+		t.Errorf("expected the source to be untouched, got:\n%s", got)
+	}
+	if !strings.Contains(mut.Diff(), "+\ta := 1 - 2") {
+		t.Errorf("expected the diff of the mutation, got:\n%s", mut.Diff())
+	}
+}
+
+func TestMutantApplyToPrerendered(t *testing.T) {
+	viperSet(map[string]any{configuration.UnleashOverlayKey: true})
+	defer viperReset()
+
+	src := "package main\n\nfunc main() {\n\ta := 1 + 2\n\tb := a > 0 && a < 10\n}\n"
+	want := []string{
+		"package main\n\nfunc main() {\n\ta := 1 - 2\n\tb := a > 0 && a < 10\n}\n",
+		"package main\n\nfunc main() {\n\ta := 1 + 2\n\tb := a >= 0 && a < 10\n}\n",
+		"package main\n\nfunc main() {\n\ta := 1 + 2\n\tb := true && a < 10\n}\n",
+	}
+
+	workdir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workdir, "sourceFile.go"), []byte(src), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	set := token.NewFileSet()
+	f, err := parser.ParseFile(set, "sourceFile.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nodes []*engine.NodeToken
+	ast.Inspect(f, func(n ast.Node) bool {
+		if n, ok := n.(*ast.BinaryExpr); ok && n.Op != token.LSS {
+			if nt, ok := engine.NewTokenNode(n); ok {
+				nodes = append(nodes, nt)
+			}
+		}
+
+		return true
+	})
+	// The nodes are 1 + 2, a > 0 && a < 10 and a > 0.
+	mutations := []struct {
+		node int
+		mt   mutator.Type
+	}{
+		{node: 0, mt: mutator.ArithmeticBase},
+		{node: 2, mt: mutator.ConditionalsBoundary},
+		{node: 1, mt: mutator.RemoveBinaryExpressionLeft},
+	}
+	var mutants []mutator.Mutator
+	for _, mu := range mutations {
+		mut := engine.NewTokenMutant("example.com/test", set, f, nodes[mu.node])
+		mut.SetType(mu.mt)
+		mut.SetStatus(mutator.Runnable)
+		mut.SetWorkdir(workdir)
+		mutants = append(mutants, mut)
+	}
+
+	mod := gomodule.GoModule{Name: "example.com", Root: workdir, CallingDir: "."}
+	engine.NewExecutorDealer(mod, newWdDealerStub(t), expectedTimeout).Prepare(mutants)
+
+	for i, m := range mutants {
+		mutatedPath := filepath.Join(t.TempDir(), "sourceFile.go")
+		if err := m.(*engine.TokenMutator).ApplyTo(mutatedPath); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(mutatedPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(string(got), want[i]) {
+			t.Error(cmp.Diff(want[i], string(got)))
+		}
+		if m.Diff() == "" {
+			t.Errorf("expected the diff of the mutation %v", m.Type())
+		}
+	}
+}
+
+func TestMutantDescribesTheMutation(t *testing.T) {
+	src := "package main\n\nfunc main() {\n\ta := 1 + 2\n\tb := a > 0 && a < 10\n}\n"
+	set := token.NewFileSet()
+	f, err := parser.ParseFile(set, "sourceFile.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := make(map[token.Token]*engine.NodeToken)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if n, ok := engine.NewTokenNode(n); ok {
+			nodes[n.Tok()] = n
+		}
+
+		return true
+	})
+
+	testCases := []struct {
+		name            string
+		tok             token.Token
+		mutantType      mutator.Type
+		wantReplacement string
+		wantStart       [2]int
+		wantEnd         [2]int
+	}{
+		{
+			name:            "token replacement",
+			tok:             token.ADD,
+			mutantType:      mutator.ArithmeticBase,
+			wantReplacement: "-",
+			wantStart:       [2]int{4, 9},
+			wantEnd:         [2]int{4, 10},
+		},
+		{
+			name:            "binary expression operand removal",
+			tok:             token.LAND,
+			mutantType:      mutator.RemoveBinaryExpressionLeft,
+			wantReplacement: "true",
+			wantStart:       [2]int{5, 7},
+			wantEnd:         [2]int{5, 12},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mut := engine.NewTokenMutant("example.com/test", set, f, nodes[tc.tok])
+			mut.SetType(tc.mutantType)
+
+			if got := mut.Replacement(); got != tc.wantReplacement {
+				t.Errorf("expected replacement %q, got %q", tc.wantReplacement, got)
+			}
+			start, end := mut.Location()
+			gotStart := [2]int{start.Line, start.Column}
+			gotEnd := [2]int{end.Line, end.Column}
+			if gotStart != tc.wantStart || gotEnd != tc.wantEnd {
+				t.Errorf("expected location %v-%v, got %v-%v", tc.wantStart, tc.wantEnd, gotStart, gotEnd)
+			}
+		})
+	}
+}
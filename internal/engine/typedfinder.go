@@ -0,0 +1,301 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// typedFinder finds the mutations of a file which depend on the types of
+// the code, so they are only found when the type information is available.
+type typedFinder struct {
+	mu      *Engine
+	pkgName string
+	set     *token.FileSet
+	file    *ast.File
+	info    *types.Info
+	pkg     *types.Package
+
+	// uses counts how many times the local variables and the imports of the
+	// file are used, to avoid removing the last use of one of them. The
+	// parameters can be left unused, so they are not considered.
+	uses   map[types.Object]int
+	params map[types.Object]bool
+
+	// swallowed contains the returned errors already replaced with nil by
+	// a SwallowError mutant.
+	swallowed map[ast.Expr]bool
+}
+
+func (mu *Engine) newTypedFinder(fileName string, set *token.FileSet, file *ast.File, info *types.Info) (*typedFinder, bool) {
+	if info == nil {
+		return nil, false
+	}
+
+	return &typedFinder{
+		mu:      mu,
+		pkgName: mu.pkgName(fileName, file.Name.Name),
+		set:     set,
+		file:    file,
+		info:    info,
+		uses:    localUses(file, info),
+		params:  params(file, info),
+
+		swallowed: make(map[ast.Expr]bool),
+	}, true
+}
+
+// funcs calls fn for each function declared in the file, with its
+// signature.
+func (r *typedFinder) funcs(fn func(body *ast.BlockStmt, sig *types.Signature)) {
+	for _, decl := range r.file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		obj, ok := r.info.Defs[fd.Name].(*types.Func)
+		if !ok {
+			continue
+		}
+		r.pkg = obj.Pkg()
+		fn(fd.Body, obj.Type().(*types.Signature))
+	}
+}
+
+// findMutations finds the mutations of the error checks, of the returned
+// values, of the calls and of the concurrency primitives. The error checks
+// are searched first, so that the returned values already mutated by them
+// are not mutated twice.
+func (r *typedFinder) findMutations() {
+	r.funcs(r.findErrorChecks)
+	r.funcs(r.findReturns)
+	r.funcs(r.findCalls)
+	r.funcs(r.findConcurrency)
+}
+
+// removesLastUse checks if the expression contains the only uses of a local
+// variable or of an import, in which case replacing it would not compile.
+// Without the type information it can't be known, so it returns false.
+func (r *typedFinder) removesLastUse(expr ast.Expr) bool {
+	if r == nil {
+		return false
+	}
+
+	return r.replacesLastUse(expr, nil)
+}
+
+// replacesLastUse checks if replacing the expression with the kept one
+// removes the only uses of a local variable or of an import.
+func (r *typedFinder) replacesLastUse(expr, kept ast.Expr) bool {
+	removed := []ast.Node{expr}
+	if kept == nil {
+		return r.replacesLastUses(removed, nil)
+	}
+
+	return r.replacesLastUses(removed, []ast.Node{kept})
+}
+
+// replacesLastUses checks if replacing the removed nodes with the kept ones
+// removes the only uses of a local variable or of an import.
+func (r *typedFinder) replacesLastUses(removed, kept []ast.Node) bool {
+	found := make(map[types.Object]int)
+	for _, node := range removed {
+		for obj, n := range localUses(node, r.info) {
+			found[obj] += n
+		}
+	}
+	for _, node := range kept {
+		for obj, n := range localUses(node, r.info) {
+			found[obj] -= n
+		}
+	}
+	for obj, n := range found {
+		if n > 0 && !r.params[obj] && n >= r.uses[obj] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// localUses counts the uses of the local variables and of the imports in
+// the node. The variables which are only assigned or incremented are not
+// considered used by the compiler, so these are not counted.
+func localUses(node ast.Node, info *types.Info) map[types.Object]int {
+	assigned := make(map[*ast.Ident]bool)
+	uses := make(map[types.Object]int)
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok {
+					assigned[id] = true
+				}
+			}
+		case *ast.IncDecStmt:
+			if id, ok := n.X.(*ast.Ident); ok {
+				assigned[id] = true
+			}
+		case *ast.Ident:
+			switch obj := info.Uses[n].(type) {
+			case *types.Var:
+				if !assigned[n] && !obj.IsField() && obj.Parent() != nil && obj.Parent() != obj.Pkg().Scope() {
+					uses[obj]++
+				}
+			case *types.PkgName:
+				uses[obj]++
+			}
+		}
+
+		return true
+	})
+
+	return uses
+}
+
+// params returns the parameters and the named results of the functions and
+// of the function literals in the node.
+func params(node ast.Node, info *types.Info) map[types.Object]bool {
+	found := make(map[types.Object]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		ft, ok := n.(*ast.FuncType)
+		if !ok {
+			return true
+		}
+		for _, fl := range []*ast.FieldList{ft.Params, ft.Results} {
+			if fl == nil {
+				continue
+			}
+			for _, f := range fl.List {
+				for _, name := range f.Names {
+					found[info.Defs[name]] = true
+				}
+			}
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// add adds the mutant of the node if its mutator.Type is enabled, and
+// returns false otherwise.
+func (r *typedFinder) add(mt mutator.Type, expr ast.Node, replacement ast.Node, mutation Mutation) bool {
+	if !configuration.Get[bool](configuration.MutantTypeEnabledKey(mt)) {
+		return false
+	}
+	m := NewASTMutator(r.pkgName, r.set, r.file, expr, replacement, mutation)
+	m.SetType(mt)
+	m.SetStatus(r.mu.mutationStatus(r.set.Position(expr.Pos())))
+
+	r.mu.mutants = append(r.mu.mutants, m)
+
+	return true
+}
+
+// typeExpr returns the expression of the type as it can be written in the
+// file, using the names of the imports of the file. It returns false if the
+// type refers to packages not imported by the file or to unexported types
+// of other packages.
+func (r *typedFinder) typeExpr(t types.Type) (ast.Expr, bool) {
+	ok := true
+	s := types.TypeString(t, func(p *types.Package) string {
+		if p == r.pkg {
+			return ""
+		}
+		for _, imp := range r.file.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			if path != p.Path() {
+				continue
+			}
+			switch name := importName(imp, p.Name()); name {
+			case ".":
+				return ""
+			case "_":
+			default:
+				return name
+			}
+		}
+		ok = false
+
+		return p.Name()
+	})
+	if !ok {
+		return nil, false
+	}
+	expr, err := parser.ParseExpr(s)
+	if err != nil {
+		return nil, false
+	}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, isSel := n.(*ast.SelectorExpr); isSel && !sel.Sel.IsExported() {
+			ok = false
+		}
+
+		return ok
+	})
+
+	return expr, ok
+}
+
+func importName(imp *ast.ImportSpec, def string) string {
+	if imp.Name != nil {
+		return imp.Name.Name
+	}
+
+	return def
+}
+
+// replaceExpr replaces the expression the pointer refers to, which is a
+// field of a node of the syntax tree.
+func replaceExpr(expr *ast.Expr, with ast.Expr) Mutation {
+	return func() func() {
+		orig := *expr
+		*expr = with
+
+		return func() {
+			*expr = orig
+		}
+	}
+}
+
+// withImport adds the import of the package to the file for the duration
+// of the Mutation.
+func withImport(file *ast.File, path string, mutation Mutation) Mutation {
+	return func() func() {
+		decls := file.Decls
+		imp := &ast.GenDecl{
+			Tok:   token.IMPORT,
+			Specs: []ast.Spec{&ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)}}},
+		}
+		file.Decls = append([]ast.Decl{imp}, decls...)
+		rollback := mutation()
+
+		return func() {
+			rollback()
+			file.Decls = decls
+		}
+	}
+}
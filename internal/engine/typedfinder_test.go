@@ -0,0 +1,101 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine_test
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"testing"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/gomodule"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// dryRun returns the mutants found in the module in dry run, with the
+// additional settings. If typed, the type information of the module is
+// loaded, so that the typed mutators find their mutants as well.
+func dryRun(t *testing.T, mod gomodule.GoModule, typed bool, settings map[string]any, opts ...engine.Option) []mutator.Mutator {
+	t.Helper()
+	set := map[string]any{configuration.UnleashDryRunKey: true}
+	for k, v := range settings {
+		set[k] = v
+	}
+	viperSet(set)
+	defer viperReset()
+
+	if typed {
+		pkgs, err := engine.LoadPackages(mod)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts = append(opts, engine.WithPackages(pkgs))
+	}
+	mut := engine.New(mod, engine.CodeData{}, newJobDealerStub(t), opts...)
+
+	return mut.Run(context.Background()).Mutants
+}
+
+// applyAndBuild applies one at a time the mutants of the given types found
+// in the module, and checks that the module builds with each of them.
+func applyAndBuild(t *testing.T, mod gomodule.GoModule, types map[mutator.Type]bool, opts ...engine.Option) []mutator.Mutator {
+	t.Helper()
+
+	var applied []mutator.Mutator
+	for _, m := range dryRun(t, mod, true, nil, opts...) {
+		if !types[m.Type()] {
+			continue
+		}
+		m.SetWorkdir(mod.Root)
+		if err := m.Apply(); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command("go", "build", "./...")
+		cmd.Dir = mod.Root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("expected %s at %s to compile, got %s\n%s", m.Type(), m.Position(), out, m.Diff())
+		}
+		if err := m.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		applied = append(applied, m)
+	}
+
+	return applied
+}
+
+// describeMutants returns the line, the type and the replacement of the
+// mutants of the given types, sorted.
+func describeMutants(mutants []mutator.Mutator, types map[mutator.Type]bool) []string {
+	var got []string
+	for _, m := range mutants {
+		if !types[m.Type()] {
+			continue
+		}
+		d, ok := m.(mutator.Describer)
+		if !ok {
+			continue
+		}
+		got = append(got, fmt.Sprintf("%d %s %s", m.Position().Line, m.Type(), d.Replacement()))
+	}
+	sort.Strings(got)
+
+	return got
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package engine
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// isTypeCompatible checks, using the type information, if the mutation
+// would produce code that compiles. Without type information, or when the
+// type can't be determined, the mutation is always considered compatible.
+//
+// It filters out the mutations that would otherwise always be NOT VIABLE:
+//   - ARITHMETIC_BASE and INVERT_ASSIGNMENTS on string concatenation;
+//   - INVERT_BITWISE and INVERT_BITWISE_ASSIGNMENTS on non-integer operands,
+//     like the address-of operator.
+func isTypeCompatible(mt mutator.Type, node *NodeToken, info *types.Info) bool {
+	if info == nil {
+		return true
+	}
+	t := operandType(*node.node, info)
+	if t == nil || t == types.Typ[types.Invalid] {
+		return true
+	}
+
+	switch mt {
+	case mutator.ArithmeticBase, mutator.InvertAssignments:
+		if node.Tok() == token.ADD || node.Tok() == token.ADD_ASSIGN {
+			return !isBasic(t, types.IsString)
+		}
+	case mutator.InvertBitwise, mutator.InvertBitwiseAssignments:
+		if _, ok := t.(*types.TypeParam); ok {
+			return true
+		}
+
+		return isBasic(t, types.IsInteger)
+	}
+
+	return true
+}
+
+// operandType returns the type the mutated token operates on.
+func operandType(node ast.Node, info *types.Info) types.Type {
+	switch n := node.(type) {
+	case *ast.BinaryExpr:
+		return info.TypeOf(n)
+	case *ast.UnaryExpr:
+		return info.TypeOf(n)
+	case *ast.AssignStmt:
+		if len(n.Lhs) == 0 {
+			return nil
+		}
+
+		return info.TypeOf(n.Lhs[0])
+	}
+
+	return nil
+}
+
+func isBasic(t types.Type, flag types.BasicInfo) bool {
+	b, ok := t.Underlying().(*types.Basic)
+
+	return ok && b.Info()&flag != 0
+}
@@ -203,3 +203,19 @@ func (fakeMutant) Apply() error {
 func (fakeMutant) Rollback() error {
 	panic("not used in test")
 }
+
+func (fakeMutant) Diff() string {
+	panic("not used in test")
+}
+
+func (fakeMutant) SetDiff(_ string) {
+	panic("not used in test")
+}
+
+func (fakeMutant) SetTestExecutionError(_ error) {
+	panic("not used in test")
+}
+
+func (fakeMutant) TestExecutionError() error {
+	panic("not used in test")
+}
@@ -2,14 +2,33 @@ package exclusion
 
 import (
 	"fmt"
+	"go/ast"
+	"path"
 	"regexp"
+	"strings"
 
 	"github.com/spf13/viper"
 
 	"github.com/singhnishant94/gremlins/internal/configuration"
 )
 
-type Rules []*regexp.Regexp
+// Rules are the rules excluding code from the Gremlins run: the files
+// matching a regexp, the packages matching an import path pattern and the
+// functions matching a name pattern.
+type Rules struct {
+	files     []*regexp.Regexp
+	packages  []string
+	functions []funcPattern
+}
+
+// funcPattern matches the name of a function, or of a method if recv is
+// set. The receiver can be "*" to match any type.
+type funcPattern struct {
+	recv    string
+	name    string
+	pointer bool
+	value   bool
+}
 
 func New() (Rules, error) {
 	var rules Rules
@@ -21,21 +40,75 @@ func New() (Rules, error) {
 	for i, s := range flagValues {
 		r, err := regexp.Compile(s)
 		if err != nil {
-			return nil, fmt.Errorf("error in exclude-files param value #%d: %w", i, err)
+			return Rules{}, fmt.Errorf("error in exclude-files param value #%d: %w", i, err)
+		}
+
+		rules.files = append(rules.files, r)
+	}
+
+	for i, s := range viper.GetStringSlice(configuration.UnleashExcludePackages) {
+		if _, err := path.Match(strings.TrimSuffix(s, "/..."), ""); err != nil {
+			return Rules{}, fmt.Errorf("error in exclude-packages param value #%d: %w", i, err)
 		}
 
-		rules = append(rules, r)
+		rules.packages = append(rules.packages, s)
+	}
+
+	for i, s := range viper.GetStringSlice(configuration.UnleashExcludeFunctions) {
+		p, err := parseFuncPattern(s)
+		if err != nil {
+			return Rules{}, fmt.Errorf("error in exclude-functions param value #%d: %w", i, err)
+		}
+
+		rules.functions = append(rules.functions, p)
 	}
 
 	return rules, nil
 }
 
+// parseFuncPattern parses the patterns of functions and methods:
+//
+//   - Name matches the functions;
+//   - (*Type).Name matches the methods with a pointer receiver;
+//   - (Type).Name matches the methods with a value receiver;
+//   - Type.Name matches the methods with both;
+//   - *.Name matches the methods of any type.
+//
+// The names and the types can contain the wildcards of path.Match.
+func parseFuncPattern(s string) (funcPattern, error) {
+	i := strings.LastIndex(s, ".")
+	p := funcPattern{name: s[i+1:], pointer: true, value: true}
+	if i >= 0 {
+		recv := s[:i]
+		if strings.HasPrefix(recv, "(") && strings.HasSuffix(recv, ")") {
+			recv = strings.TrimSuffix(strings.TrimPrefix(recv, "("), ")")
+			p.pointer = strings.HasPrefix(recv, "*")
+			p.value = !p.pointer
+			recv = strings.TrimPrefix(recv, "*")
+		}
+		p.recv = recv
+		if _, err := path.Match(p.recv, ""); err != nil || p.recv == "" {
+			return funcPattern{}, fmt.Errorf("invalid receiver in %q", s)
+		}
+	}
+	if _, err := path.Match(p.name, ""); err != nil || p.name == "" {
+		return funcPattern{}, fmt.Errorf("invalid name in %q", s)
+	}
+
+	return p, nil
+}
+
+// IsEmpty checks if there are no rules.
+func (r Rules) IsEmpty() bool {
+	return len(r.files) == 0 && len(r.packages) == 0 && len(r.functions) == 0
+}
+
 func (r Rules) IsFileExcluded(path string) bool {
-	if len(r) == 0 {
+	if len(r.files) == 0 {
 		return false
 	}
 
-	for _, rule := range r {
+	for _, rule := range r.files {
 		if rule.MatchString(path) {
 			return true
 		}
@@ -43,3 +116,74 @@ func (r Rules) IsFileExcluded(path string) bool {
 
 	return false
 }
+
+// IsPackageExcluded checks if the import path of the package matches one of
+// the patterns. The patterns can contain the wildcards of path.Match and, as
+// for the go command, end with /... to match the subpackages too.
+func (r Rules) IsPackageExcluded(pkg string) bool {
+	for _, p := range r.packages {
+		base, sub := strings.CutSuffix(p, "/...")
+		if ok, _ := path.Match(base, pkg); ok {
+			return true
+		}
+		if !sub {
+			continue
+		}
+		for dir := path.Dir(pkg); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if ok, _ := path.Match(base, dir); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsFuncExcluded checks if the function declaration matches one of the
+// patterns.
+func (r Rules) IsFuncExcluded(fd *ast.FuncDecl) bool {
+	recv, pointer := receiver(fd)
+	for _, p := range r.functions {
+		if (p.recv == "") != (recv == "") {
+			continue
+		}
+		if ok, _ := path.Match(p.name, fd.Name.Name); !ok {
+			continue
+		}
+		if recv == "" {
+			return true
+		}
+		if pointer && !p.pointer || !pointer && !p.value {
+			continue
+		}
+		if ok, _ := path.Match(p.recv, recv); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// receiver returns the name of the type of the receiver of the method, and
+// whether the receiver is a pointer. The name is empty for the functions.
+func receiver(fd *ast.FuncDecl) (string, bool) {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return "", false
+	}
+	typ := fd.Recv.List[0].Type
+	star, pointer := typ.(*ast.StarExpr)
+	if pointer {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name, pointer
+	}
+
+	return "", false
+}
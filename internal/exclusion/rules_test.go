@@ -1,8 +1,14 @@
 package exclusion
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/singhnishant94/gremlins/internal/configuration"
 )
 
@@ -28,7 +34,7 @@ func TestRules_IsFileExcluded(t *testing.T) {
 		configuration.Set(configuration.UnleashExcludeFiles, ss)
 
 		rules, err := New()
-		if err == nil || rules != nil {
+		if err == nil || !rules.IsEmpty() {
 			t.Error("must return error")
 		}
 	})
@@ -37,7 +43,7 @@ func TestRules_IsFileExcluded(t *testing.T) {
 		configuration.Set(configuration.UnleashExcludeFiles, []string(nil))
 
 		rules, err := New()
-		if err != nil || len(rules) != 0 {
+		if err != nil || !rules.IsEmpty() {
 			t.Error("must return empty rules")
 		}
 
@@ -48,6 +54,152 @@ func TestRules_IsFileExcluded(t *testing.T) {
 
 }
 
+func TestRules_IsPackageExcluded(t *testing.T) {
+	configuration.Set(configuration.UnleashExcludePackages, []any{
+		"example.com/mod/internal/gen/...",
+		"example.com/mod/*pb",
+		"example.com/mod/mocks",
+	})
+	defer configuration.Set(configuration.UnleashExcludePackages, []string(nil))
+
+	rules, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		pkg  string
+		want bool
+	}{
+		{pkg: "example.com/mod/internal/gen", want: true},
+		{pkg: "example.com/mod/internal/gen/api", want: true},
+		{pkg: "example.com/mod/internal/generator", want: false},
+		{pkg: "example.com/mod/userpb", want: true},
+		{pkg: "example.com/mod/userpb/v2", want: false},
+		{pkg: "example.com/mod/mocks", want: true},
+		{pkg: "example.com/mod/mocks/db", want: false},
+		{pkg: "example.com/mod", want: false},
+	}
+	for _, tc := range testCases {
+		if got := rules.IsPackageExcluded(tc.pkg); got != tc.want {
+			t.Errorf("IsPackageExcluded(%q) = %v, want %v", tc.pkg, got, tc.want)
+		}
+	}
+}
+
+func TestRules_IsFuncExcluded(t *testing.T) {
+	const src = `package p
+
+func String() string { return "" }
+func (s *Server) String() string { return "" }
+func (s Server) Addr() string { return "" }
+func (s *Server) Addr2() string { return "" }
+func (m Message) MarshalJSON() ([]byte, error) { return nil, nil }
+func (m *Message) GetName() string { return "" }
+func (s *Stack[T]) Push(v T) {}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, decl := range file.Decls {
+		fd := decl.(*ast.FuncDecl)
+		name := fd.Name.Name
+		if recv, pointer := receiver(fd); recv != "" {
+			if pointer {
+				recv = "*" + recv
+			}
+			name = "(" + recv + ")." + name
+		}
+		funcs[name] = fd
+	}
+
+	testCases := []struct {
+		name     string
+		patterns []any
+		want     []string
+	}{
+		{
+			name:     "pointer receiver",
+			patterns: []any{"(*Server).String"},
+			want:     []string{"(*Server).String"},
+		},
+		{
+			name:     "value receiver",
+			patterns: []any{"(Server).Addr*"},
+			want:     []string{"(Server).Addr"},
+		},
+		{
+			name:     "any receiver kind",
+			patterns: []any{"Server.*"},
+			want:     []string{"(*Server).Addr2", "(*Server).String", "(Server).Addr"},
+		},
+		{
+			name:     "any receiver type",
+			patterns: []any{"*.MarshalJSON", "*.Get*"},
+			want:     []string{"(*Message).GetName", "(Message).MarshalJSON"},
+		},
+		{
+			name:     "function",
+			patterns: []any{"String"},
+			want:     []string{"String"},
+		},
+		{
+			name:     "generic receiver",
+			patterns: []any{"(*Stack).Push"},
+			want:     []string{"(*Stack).Push"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			configuration.Set(configuration.UnleashExcludeFunctions, tc.patterns)
+			defer configuration.Set(configuration.UnleashExcludeFunctions, []string(nil))
+
+			rules, err := New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for name, fd := range funcs {
+				if rules.IsFuncExcluded(fd) {
+					got = append(got, name)
+				}
+			}
+			sort.Strings(got)
+
+			if !cmp.Equal(got, tc.want) {
+				t.Error(cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		key  string
+		val  []any
+	}{
+		{name: "invalid package pattern", key: configuration.UnleashExcludePackages, val: []any{"example.com/[/..."}},
+		{name: "invalid function name", key: configuration.UnleashExcludeFunctions, val: []any{"(*Server).["}},
+		{name: "empty function name", key: configuration.UnleashExcludeFunctions, val: []any{"Server."}},
+		{name: "invalid receiver", key: configuration.UnleashExcludeFunctions, val: []any{"[.String"}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			configuration.Set(tc.key, tc.val)
+			defer configuration.Set(tc.key, []string(nil))
+
+			if _, err := New(); err == nil {
+				t.Error("must return error")
+			}
+		})
+	}
+}
+
 func countTrue(ss []string, f func(s string) bool) int {
 	count := 0
 
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultBaseURL is the base URL of the public GitHub REST API.
+const DefaultBaseURL = "https://api.github.com"
+
+const commentsPerPage = 100
+
+// ErrInvalidRepo is returned when the repository is not in the owner/name format.
+var ErrInvalidRepo = errors.New("invalid GitHub repository, expected format is 'owner/name'")
+
+// PullRequest is the subset of a GitHub pull request used by Gremlins.
+type PullRequest struct {
+	State  string `json:"state"`
+	Base   Ref    `json:"base"`
+	Head   Ref    `json:"head"`
+	Number int    `json:"number"`
+}
+
+// Ref is a git reference of a PullRequest.
+type Ref struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+// Review is a pull request review containing a batch of Comment.
+type Review struct {
+	CommitID string    `json:"commit_id"`
+	Body     string    `json:"body"`
+	Event    string    `json:"event"`
+	Comments []Comment `json:"comments"`
+}
+
+// Client is the subset of the GitHub API used to publish the results.
+type Client interface {
+	// PullRequestsForCommit lists the pull requests associated with a commit.
+	PullRequestsForCommit(ctx context.Context, sha string) ([]PullRequest, error)
+
+	// ReviewComments lists all the review comments of a pull request.
+	ReviewComments(ctx context.Context, number int) ([]Comment, error)
+
+	// CreateReview creates a new review on a pull request.
+	CreateReview(ctx context.Context, number int, review Review) error
+}
+
+// RESTClient is the implementation of Client using the GitHub REST API.
+type RESTClient struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+	owner      string
+	repo       string
+}
+
+// ClientOption for the RESTClient initialization.
+type ClientOption func(c *RESTClient) *RESTClient
+
+// WithBaseURL overrides the GitHub API base URL, for example for GitHub
+// Enterprise or a stub server in tests.
+func WithBaseURL(u string) ClientOption {
+	return func(c *RESTClient) *RESTClient {
+		c.baseURL = strings.TrimSuffix(u, "/")
+
+		return c
+	}
+}
+
+// WithHTTPClient overrides the default http.Client.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *RESTClient) *RESTClient {
+		c.httpClient = hc
+
+		return c
+	}
+}
+
+// NewClient instantiates a RESTClient for the given repository, in the
+// owner/name format, authenticated with the given token.
+func NewClient(repo, token string, opts ...ClientOption) (*RESTClient, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, ErrInvalidRepo
+	}
+	c := &RESTClient{
+		httpClient: http.DefaultClient,
+		baseURL:    DefaultBaseURL,
+		token:      token,
+		owner:      owner,
+		repo:       name,
+	}
+	for _, opt := range opts {
+		c = opt(c)
+	}
+
+	return c, nil
+}
+
+// PullRequestsForCommit lists the pull requests associated with a commit.
+func (c *RESTClient) PullRequestsForCommit(ctx context.Context, sha string) ([]PullRequest, error) {
+	var prs []PullRequest
+	path := fmt.Sprintf("/repos/%s/%s/commits/%s/pulls", c.owner, c.repo, sha)
+	if err := c.do(ctx, http.MethodGet, path, nil, &prs); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// ReviewComments lists all the review comments of a pull request, following
+// the pagination.
+func (c *RESTClient) ReviewComments(ctx context.Context, number int) ([]Comment, error) {
+	var result []Comment
+	for page := 1; ; page++ {
+		var comments []Comment
+		path := fmt.Sprintf("/repos/%s/%s/pulls/%d/comments?per_page=%d&page=%d",
+			c.owner, c.repo, number, commentsPerPage, page)
+		if err := c.do(ctx, http.MethodGet, path, nil, &comments); err != nil {
+			return nil, err
+		}
+		result = append(result, comments...)
+		if len(comments) < commentsPerPage {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// CreateReview creates a new review on a pull request.
+func (c *RESTClient) CreateReview(ctx context.Context, number int, review Review) error {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews", c.owner, c.repo, number)
+
+	return c.do(ctx, http.MethodPost, path, review, nil)
+}
+
+func (c *RESTClient) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func(b io.ReadCloser) {
+		_ = b.Close()
+	}(res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		msg, _ := io.ReadAll(res.Body)
+
+		return fmt.Errorf("%s %s: unexpected status %d: %s", method, path, res.StatusCode, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package github
+
+import (
+	"fmt"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// SideRight is the side of the diff on which the comments are placed. Since
+// Gremlins only mutates the current version of the code, it is always the
+// right side.
+const SideRight = "RIGHT"
+
+// Comment is a single inline review comment on a pull request.
+type Comment struct {
+	Body string `json:"body"`
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side"`
+}
+
+// Comments gathers a Comment for each LIVED mutator.Mutator.
+//
+// Only one Comment per line is generated: if more than one mutant lived on
+// the same line, only the first one is reported, since the reviewer will
+// need to look at the line anyway.
+func Comments(mutants []mutator.Mutator) []Comment {
+	surfaced := map[string]map[int]bool{}
+	comments := []Comment{}
+
+	for _, m := range mutants {
+		if m.Status() != mutator.Lived {
+			continue
+		}
+		pos := m.Position()
+		fileLines, ok := surfaced[pos.Filename]
+		if !ok {
+			fileLines = map[int]bool{}
+			surfaced[pos.Filename] = fileLines
+		}
+		if fileLines[pos.Line] {
+			continue
+		}
+		fileLines[pos.Line] = true
+
+		comments = append(comments, Comment{
+			Body: commentBody(m),
+			Path: pos.Filename,
+			Line: pos.Line,
+			Side: SideRight,
+		})
+	}
+
+	return comments
+}
+
+func commentBody(m mutator.Mutator) string {
+	return fmt.Sprintf(
+		"[gremlins] Changing the code like shown below does not cause any tests exercising them to fail.\n"+
+			"Consider adding tests that fail when the code is mutated.\n\n"+
+			"```diff\n%s\n```", m.Diff())
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package github_test
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/singhnishant94/gremlins/internal/github"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+func TestComments(t *testing.T) {
+	mutants := []mutator.Mutator{
+		stubMutant{status: mutator.Lived, position: newPosition("file1.go", 10), diff: "first"},
+		stubMutant{status: mutator.Lived, position: newPosition("file1.go", 10), diff: "same line"},
+		stubMutant{status: mutator.Killed, position: newPosition("file1.go", 12), diff: "killed"},
+		stubMutant{status: mutator.NotCovered, position: newPosition("file1.go", 13), diff: "not covered"},
+		stubMutant{status: mutator.Lived, position: newPosition("file1.go", 14), diff: "other line"},
+		stubMutant{status: mutator.Lived, position: newPosition("file2.go", 10), diff: "other file"},
+	}
+
+	got := github.Comments(mutants)
+
+	want := []struct {
+		path string
+		diff string
+		line int
+	}{
+		{path: "file1.go", line: 10, diff: "first"},
+		{path: "file1.go", line: 14, diff: "other line"},
+		{path: "file2.go", line: 10, diff: "other file"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %d comments, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		if got[i].Path != w.path || got[i].Line != w.line {
+			t.Errorf("want comment at %s:%d, got %s:%d", w.path, w.line, got[i].Path, got[i].Line)
+		}
+		if got[i].Side != github.SideRight {
+			t.Errorf("want side %q, got %q", github.SideRight, got[i].Side)
+		}
+		if !strings.Contains(got[i].Body, "```diff\n"+w.diff+"\n```") {
+			t.Errorf("expected body to contain the diff %q, got %q", w.diff, got[i].Body)
+		}
+	}
+}
+
+func newPosition(filename string, line int) token.Position {
+	return token.Position{
+		Filename: filename,
+		Line:     line,
+		Column:   3,
+	}
+}
+
+type stubMutant struct {
+	position token.Position
+	diff     string
+	status   mutator.Status
+}
+
+func (stubMutant) Type() mutator.Type {
+	return mutator.ConditionalsBoundary
+}
+
+func (stubMutant) SetType(_ mutator.Type) {
+	panic("not used in test")
+}
+
+func (s stubMutant) Status() mutator.Status {
+	return s.status
+}
+
+func (stubMutant) SetStatus(_ mutator.Status) {
+	panic("not used in test")
+}
+
+func (s stubMutant) Position() token.Position {
+	return s.position
+}
+
+func (stubMutant) Pos() token.Pos {
+	panic("not used in test")
+}
+
+func (s stubMutant) Diff() string {
+	return s.diff
+}
+
+func (stubMutant) SetDiff(_ string) {
+	panic("not used in test")
+}
+
+func (stubMutant) Pkg() string {
+	panic("not used in test")
+}
+
+func (stubMutant) SetWorkdir(_ string) {
+	panic("not used in test")
+}
+
+func (stubMutant) Workdir() string {
+	panic("not used in test")
+}
+
+func (stubMutant) Apply() error {
+	panic("not used in test")
+}
+
+func (stubMutant) Rollback() error {
+	panic("not used in test")
+}
+
+func (stubMutant) SetTestExecutionError(_ error) {
+	panic("not used in test")
+}
+
+func (stubMutant) TestExecutionError() error {
+	panic("not used in test")
+}
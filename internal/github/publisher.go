@@ -0,0 +1,205 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/singhnishant94/gremlins/internal/log"
+)
+
+// ReviewEvent is the event used to create the review. COMMENT doesn't
+// approve nor request changes, leaving the decision to the reviewers.
+const ReviewEvent = "COMMENT"
+
+// ErrPullRequestNotFound is returned when no open pull request can be
+// associated with the current commit and the diff reference.
+var ErrPullRequestNotFound = errors.New("no open pull request found for the current commit")
+
+type execContext = func(name string, args ...string) *exec.Cmd
+
+// Publisher publishes the Comment of a Gremlins run as a single review on
+// the pull request the current commit belongs to.
+type Publisher struct {
+	client  Client
+	cmd     execContext
+	diffRef string
+	dir     string
+}
+
+// PublisherOption for the Publisher initialization.
+type PublisherOption func(p *Publisher) *Publisher
+
+// WithExecContext overrides the default exec.Command used to call git.
+func WithExecContext(c execContext) PublisherOption {
+	return func(p *Publisher) *Publisher {
+		p.cmd = c
+
+		return p
+	}
+}
+
+// WithDir sets the directory in which git is called. It must be the directory
+// the Comment paths are relative to.
+func WithDir(dir string) PublisherOption {
+	return func(p *Publisher) *Publisher {
+		p.dir = dir
+
+		return p
+	}
+}
+
+// NewPublisher instantiates a Publisher. The diffRef is the same reference
+// used to calculate the diff, and it is used to select the pull request
+// targeting it.
+func NewPublisher(client Client, diffRef string, opts ...PublisherOption) *Publisher {
+	p := &Publisher{
+		client:  client,
+		cmd:     exec.Command,
+		diffRef: diffRef,
+	}
+	for _, opt := range opts {
+		p = opt(p)
+	}
+
+	return p
+}
+
+// Publish creates a review with all the Comment that are not already
+// present on the pull request. Comments published by previous runs on the
+// same lines with the same body are skipped.
+func (p *Publisher) Publish(ctx context.Context, comments []Comment) error {
+	if len(comments) == 0 {
+		log.Infoln("No comments to publish on GitHub.")
+
+		return nil
+	}
+	pr, err := p.pullRequest(ctx)
+	if err != nil {
+		return err
+	}
+	prefix, err := p.git("rev-parse", "--show-prefix")
+	if err != nil {
+		return err
+	}
+
+	existing, err := p.client.ReviewComments(ctx, pr.Number)
+	if err != nil {
+		return fmt.Errorf("impossible to list review comments: %w", err)
+	}
+	published := make(map[Comment]bool, len(existing))
+	for _, c := range existing {
+		published[key(c)] = true
+	}
+
+	var newComments []Comment
+	for _, c := range comments {
+		c.Path = path.Join(prefix, c.Path)
+		if published[key(c)] {
+			continue
+		}
+		published[key(c)] = true
+		newComments = append(newComments, c)
+	}
+	if len(newComments) == 0 {
+		log.Infof("All the comments are already published on pull request #%d.\n", pr.Number)
+
+		return nil
+	}
+
+	review := Review{
+		CommitID: pr.Head.SHA,
+		Body:     fmt.Sprintf("[gremlins] %d mutants survived in this change.", len(newComments)),
+		Event:    ReviewEvent,
+		Comments: newComments,
+	}
+	if err := p.client.CreateReview(ctx, pr.Number, review); err != nil {
+		return fmt.Errorf("impossible to create review: %w", err)
+	}
+	log.Infof("Published %d comments on pull request #%d.\n", len(newComments), pr.Number)
+
+	return nil
+}
+
+// pullRequest finds the open pull request the current commit belongs to.
+// When running on a merge commit, like GitHub Actions does for pull
+// requests, the second parent is the head of the pull request, so both are
+// checked.
+func (p *Publisher) pullRequest(ctx context.Context) (PullRequest, error) {
+	head, err := p.git("rev-parse", "HEAD")
+	if err != nil {
+		return PullRequest{}, err
+	}
+	shas := []string{head}
+	if parent, err := p.git("rev-parse", "--verify", "--quiet", "HEAD^2"); err == nil && parent != "" {
+		shas = append(shas, parent)
+	}
+
+	var open []PullRequest
+	for _, sha := range shas {
+		prs, err := p.client.PullRequestsForCommit(ctx, sha)
+		if err != nil {
+			return PullRequest{}, fmt.Errorf("impossible to list pull requests: %w", err)
+		}
+		for _, pr := range prs {
+			if pr.State != "open" {
+				continue
+			}
+			if p.isBase(pr.Base.Ref) {
+				return pr, nil
+			}
+			open = append(open, pr)
+		}
+	}
+	if len(open) == 1 {
+		return open[0], nil
+	}
+
+	return PullRequest{}, ErrPullRequestNotFound
+}
+
+// isBase checks if the diff reference points to the given branch, either
+// directly (main) or through a remote (origin/main, refs/remotes/origin/main).
+func (p *Publisher) isBase(branch string) bool {
+	if p.diffRef == "" || branch == "" {
+		return false
+	}
+
+	return p.diffRef == branch || strings.HasSuffix(p.diffRef, "/"+branch)
+}
+
+func (p *Publisher) git(args ...string) (string, error) {
+	cmd := p.cmd("git", args...)
+	cmd.Dir = p.dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("an error occurred while calling git %s: %w", strings.Join(args, " "), err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+func key(c Comment) Comment {
+	c.Side = ""
+
+	return c
+}
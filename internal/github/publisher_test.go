@@ -0,0 +1,343 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/singhnishant94/gremlins/internal/github"
+)
+
+const (
+	headSHA   = "head-sha"
+	parentSHA = "parent-sha"
+)
+
+func TestPublish(t *testing.T) {
+	comment := github.Comment{Body: "body", Path: "file.go", Line: 10, Side: github.SideRight}
+	otherComment := github.Comment{Body: "body", Path: "file.go", Line: 12, Side: github.SideRight}
+
+	testCases := []struct {
+		name         string
+		git          map[string]string
+		pulls        map[string][]github.PullRequest
+		existing     []github.Comment
+		comments     []github.Comment
+		diffRef      string
+		wantReview   *github.Review
+		wantErr      error
+		wantAnyError bool
+	}{
+		{
+			name:     "publishes the comments on the open pull request targeting the diff reference",
+			git:      map[string]string{"rev-parse HEAD": headSHA, "rev-parse --show-prefix": ""},
+			diffRef:  "origin/main",
+			comments: []github.Comment{comment, otherComment},
+			pulls: map[string][]github.PullRequest{
+				headSHA: {
+					pullRequest(1, "open", "develop"),
+					pullRequest(2, "open", "main"),
+				},
+			},
+			wantReview: &github.Review{
+				CommitID: "head-2",
+				Body:     "[gremlins] 2 mutants survived in this change.",
+				Event:    github.ReviewEvent,
+				Comments: []github.Comment{comment, otherComment},
+			},
+		},
+		{
+			name:     "uses the single open pull request when none targets the diff reference",
+			git:      map[string]string{"rev-parse HEAD": headSHA, "rev-parse --show-prefix": ""},
+			comments: []github.Comment{comment},
+			pulls: map[string][]github.PullRequest{
+				headSHA: {
+					pullRequest(1, "closed", "main"),
+					pullRequest(2, "open", "develop"),
+				},
+			},
+			wantReview: &github.Review{
+				CommitID: "head-2",
+				Body:     "[gremlins] 1 mutants survived in this change.",
+				Event:    github.ReviewEvent,
+				Comments: []github.Comment{comment},
+			},
+		},
+		{
+			name: "looks for the pull request of the second parent of a merge commit",
+			git: map[string]string{
+				"rev-parse HEAD":                    headSHA,
+				"rev-parse --verify --quiet HEAD^2": parentSHA,
+				"rev-parse --show-prefix":           "sub/",
+			},
+			diffRef:  "main",
+			comments: []github.Comment{comment},
+			pulls: map[string][]github.PullRequest{
+				parentSHA: {pullRequest(3, "open", "main")},
+			},
+			wantReview: &github.Review{
+				CommitID: "head-3",
+				Body:     "[gremlins] 1 mutants survived in this change.",
+				Event:    github.ReviewEvent,
+				Comments: []github.Comment{{Body: "body", Path: "sub/file.go", Line: 10, Side: github.SideRight}},
+			},
+		},
+		{
+			name:     "skips the comments already published",
+			git:      map[string]string{"rev-parse HEAD": headSHA, "rev-parse --show-prefix": ""},
+			diffRef:  "main",
+			comments: []github.Comment{comment, otherComment},
+			existing: []github.Comment{{Body: "body", Path: "file.go", Line: 10}},
+			pulls: map[string][]github.PullRequest{
+				headSHA: {pullRequest(2, "open", "main")},
+			},
+			wantReview: &github.Review{
+				CommitID: "head-2",
+				Body:     "[gremlins] 1 mutants survived in this change.",
+				Event:    github.ReviewEvent,
+				Comments: []github.Comment{otherComment},
+			},
+		},
+		{
+			name:     "doesn't create a review if all comments are already published",
+			git:      map[string]string{"rev-parse HEAD": headSHA, "rev-parse --show-prefix": ""},
+			diffRef:  "main",
+			comments: []github.Comment{comment},
+			existing: []github.Comment{comment},
+			pulls: map[string][]github.PullRequest{
+				headSHA: {pullRequest(2, "open", "main")},
+			},
+		},
+		{
+			name:     "doesn't call GitHub if there are no comments",
+			git:      map[string]string{},
+			comments: []github.Comment{},
+		},
+		{
+			name:     "fails if no open pull request can be found",
+			git:      map[string]string{"rev-parse HEAD": headSHA, "rev-parse --show-prefix": ""},
+			diffRef:  "main",
+			comments: []github.Comment{comment},
+			pulls: map[string][]github.PullRequest{
+				headSHA: {
+					pullRequest(1, "open", "develop"),
+					pullRequest(2, "open", "release"),
+				},
+			},
+			wantErr: github.ErrPullRequestNotFound,
+		},
+		{
+			name:         "fails if git fails",
+			git:          map[string]string{},
+			comments:     []github.Comment{comment},
+			wantAnyError: true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newGitHubStub(t, tc.pulls, tc.existing)
+			client, err := github.NewClient("owner/repo", "a-token", github.WithBaseURL(srv.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := github.NewPublisher(client, tc.diffRef, github.WithExecContext(fakeGit(tc.git)))
+
+			err = p.Publish(context.Background(), tc.comments)
+
+			if tc.wantAnyError {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				return
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if !cmp.Equal(srv.review, tc.wantReview) {
+				t.Error(cmp.Diff(tc.wantReview, srv.review))
+			}
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	testCases := []struct {
+		repo    string
+		wantErr bool
+	}{
+		{repo: "owner/repo"},
+		{repo: "owner", wantErr: true},
+		{repo: "/repo", wantErr: true},
+		{repo: "owner/", wantErr: true},
+		{repo: "owner/repo/other", wantErr: true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.repo, func(t *testing.T) {
+			_, err := github.NewClient(tc.repo, "token")
+			if tc.wantErr != errors.Is(err, github.ErrInvalidRepo) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestClientReturnsErrorOnFailedRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+	}))
+	t.Cleanup(srv.Close)
+	client, _ := github.NewClient("owner/repo", "token", github.WithBaseURL(srv.URL))
+
+	_, err := client.PullRequestsForCommit(context.Background(), headSHA)
+
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected an error containing the status, got %v", err)
+	}
+}
+
+func TestClientFollowsReviewCommentsPagination(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		n := 100
+		if page == "2" {
+			n = 1
+		}
+		_ = json.NewEncoder(w).Encode(make([]github.Comment, n))
+	}))
+	t.Cleanup(srv.Close)
+	client, _ := github.NewClient("owner/repo", "token", github.WithBaseURL(srv.URL))
+
+	got, err := client.ReviewComments(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 101 {
+		t.Errorf("expected 101 comments, got %d", len(got))
+	}
+	if !cmp.Equal(pages, []string{"1", "2"}) {
+		t.Errorf("unexpected pages requested: %v", pages)
+	}
+}
+
+func pullRequest(number int, state, base string) github.PullRequest {
+	return github.PullRequest{
+		Number: number,
+		State:  state,
+		Base:   github.Ref{Ref: base},
+		Head:   github.Ref{SHA: fmt.Sprintf("head-%d", number)},
+	}
+}
+
+type gitHubStub struct {
+	*httptest.Server
+	review *github.Review
+	mu     sync.Mutex
+}
+
+func newGitHubStub(t *testing.T, pulls map[string][]github.PullRequest, existing []github.Comment) *gitHubStub {
+	t.Helper()
+	s := &gitHubStub{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/commits/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer a-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+		sha := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/commits/"), "/pulls")
+		prs := pulls[sha]
+		if prs == nil {
+			prs = []github.PullRequest{}
+		}
+		_ = json.NewEncoder(w).Encode(prs)
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/comments"):
+			c := existing
+			if c == nil {
+				c = []github.Comment{}
+			}
+			_ = json.NewEncoder(w).Encode(c)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/reviews"):
+			review := &github.Review{}
+			if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+			s.mu.Lock()
+			s.review = review
+			s.mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("{}"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func TestGitProcess(_ *testing.T) {
+	if os.Getenv("GO_TEST_PROCESS") != "1" {
+		return
+	}
+	out, ok := os.LookupEnv("GIT_OUTPUT")
+	if !ok {
+		os.Exit(1) // skipcq: RVV-A0003
+	}
+	fmt.Println(out)
+	os.Exit(0) // skipcq: RVV-A0003
+}
+
+// fakeGit returns an exec context that prints the output associated to the
+// git arguments, failing when the arguments are not in the map.
+func fakeGit(outputs map[string]string) func(name string, args ...string) *exec.Cmd {
+	return func(command string, args ...string) *exec.Cmd {
+		cs := []string{"-test.run=TestGitProcess", "--", command}
+		cs = append(cs, args...)
+		// #nosec G204 - We are in tests, we don't care
+		cmd := exec.Command(os.Args[0], cs...)
+		cmd.Env = []string{"GO_TEST_PROCESS=1"}
+		if out, ok := outputs[strings.Join(args, " ")]; ok {
+			cmd.Env = append(cmd.Env, "GIT_OUTPUT="+out)
+		}
+
+		return cmd
+	}
+}
@@ -16,7 +16,11 @@
 
 package mutator
 
-import "go/token"
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+)
 
 // Status represents the status of a given TokenMutant.
 //
@@ -28,6 +32,10 @@ import "go/token"
 //     means the test suite is not effective in catching it.
 //   - Killed means that the TokenMutant has been tested and the tests failed, which
 //     means they are effective in covering this regression.
+//   - Ignored means that the TokenMutant is suppressed by a gremlins:ignore
+//     comment, so it is not tested.
+//   - Equivalent means that the TokenMutant lived, but it compiles to the
+//     same code as the original, so no test can catch it.
 type Status int
 
 // Currently supported MutantStatus.
@@ -39,6 +47,8 @@ const (
 	Killed
 	NotViable
 	TimedOut
+	Ignored
+	Equivalent
 )
 
 func (ms Status) String() string {
@@ -57,11 +67,61 @@ func (ms Status) String() string {
 		return "NOT VIABLE"
 	case TimedOut:
 		return "TIMED OUT"
+	case Ignored:
+		return "IGNORED"
+	case Equivalent:
+		return "EQUIVALENT"
 	default:
 		panic("this should not happen")
 	}
 }
 
+// customNames are the names of the registered Type.
+var customNames = make(map[Type]string)
+
+var typeName = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+
+// Register adds a new Type with the given name. The name is shown in the
+// reports, and the configuration key and the flag of the Type are derived
+// from it, so it must be in upper snake case and not used by other Type.
+//
+// Register is meant to be called when the program is initialised, before
+// the flags are set. It panics if the name is not valid.
+func Register(name string) Type {
+	if err := CheckName(name); err != nil {
+		panic(fmt.Sprintf("mutator: %s", err))
+	}
+	mt := firstCustom + Type(len(customNames))
+	customNames[mt] = name
+	Types = append(Types, mt)
+
+	return mt
+}
+
+// CheckName returns an error if the name can't be given to a new Type,
+// because it is not in upper snake case or it is already used.
+func CheckName(name string) error {
+	if !typeName.MatchString(name) {
+		return fmt.Errorf("invalid name %q, it must be in upper snake case", name)
+	}
+	if _, ok := Lookup(name); ok {
+		return fmt.Errorf("the name %q is already used", name)
+	}
+
+	return nil
+}
+
+// Lookup returns the Type with the given name.
+func Lookup(name string) (Type, bool) {
+	for _, mt := range Types {
+		if mt.String() == name {
+			return mt, true
+		}
+	}
+
+	return 0, false
+}
+
 // Type represents the category of the TokenMutant.
 //
 // A single token.Token can be mutated in various ways depending on the
@@ -86,9 +146,33 @@ const (
 	RemoveBinaryExpressionLeft
 	RemoveBinaryExpressionRight
 	RemoveStatement
+	ReturnZeroValue
+	ReturnNegateBool
+	ReturnNilError
+	ReturnError
+	ReturnEmptyCollection
+	RemoveErrorCheck
+	SwallowError
+	UnwrapError
+	ConditionTrue
+	ConditionFalse
+	NumericLiteral
+	StringLiteral
+	BooleanLiteral
+	CallZeroValue
+	SwapArguments
+	DropVariadic
+	RemoveLock
+	SyncGoroutine
+	ChannelCapacity
+	RemoveClose
+	RemoveWaitGroup
+
+	// firstCustom is the first Type given to the registered ones.
+	firstCustom
 )
 
-// Types allows to iterate over Type.
+// Types allows to iterate over Type. It contains the registered Type too.
 var Types = []Type{
 	ArithmeticBase,
 	ConditionalsBoundary,
@@ -104,6 +188,27 @@ var Types = []Type{
 	RemoveBinaryExpressionLeft,
 	RemoveBinaryExpressionRight,
 	RemoveStatement,
+	ReturnZeroValue,
+	ReturnNegateBool,
+	ReturnNilError,
+	ReturnError,
+	ReturnEmptyCollection,
+	RemoveErrorCheck,
+	SwallowError,
+	UnwrapError,
+	ConditionTrue,
+	ConditionFalse,
+	NumericLiteral,
+	StringLiteral,
+	BooleanLiteral,
+	CallZeroValue,
+	SwapArguments,
+	DropVariadic,
+	RemoveLock,
+	SyncGoroutine,
+	ChannelCapacity,
+	RemoveClose,
+	RemoveWaitGroup,
 }
 
 func (mt Type) String() string {
@@ -136,8 +241,53 @@ func (mt Type) String() string {
 		return "REMOVE_BINARY_EXPRESSION_RIGHT"
 	case RemoveStatement:
 		return "REMOVE_STATEMENT"
+	case ReturnZeroValue:
+		return "RETURN_ZERO_VALUE"
+	case ReturnNegateBool:
+		return "RETURN_NEGATE_BOOL"
+	case ReturnNilError:
+		return "RETURN_NIL_ERROR"
+	case ReturnError:
+		return "RETURN_ERROR"
+	case ReturnEmptyCollection:
+		return "RETURN_EMPTY_COLLECTION"
+	case RemoveErrorCheck:
+		return "REMOVE_ERROR_CHECK"
+	case SwallowError:
+		return "SWALLOW_ERROR"
+	case UnwrapError:
+		return "UNWRAP_ERROR"
+	case ConditionTrue:
+		return "CONDITION_TRUE"
+	case ConditionFalse:
+		return "CONDITION_FALSE"
+	case NumericLiteral:
+		return "NUMERIC_LITERAL"
+	case StringLiteral:
+		return "STRING_LITERAL"
+	case BooleanLiteral:
+		return "BOOLEAN_LITERAL"
+	case CallZeroValue:
+		return "CALL_ZERO_VALUE"
+	case SwapArguments:
+		return "SWAP_ARGUMENTS"
+	case DropVariadic:
+		return "DROP_VARIADIC"
+	case RemoveLock:
+		return "REMOVE_LOCK"
+	case SyncGoroutine:
+		return "SYNC_GOROUTINE"
+	case ChannelCapacity:
+		return "CHANNEL_CAPACITY"
+	case RemoveClose:
+		return "REMOVE_CLOSE"
+	case RemoveWaitGroup:
+		return "REMOVE_WAITGROUP"
 
 	default:
+		if name, ok := customNames[mt]; ok {
+			return name
+		}
 		panic("this should not happen")
 	}
 }
@@ -193,3 +343,16 @@ type Mutator interface {
 	// Test execution error
 	TestExecutionError() error
 }
+
+// Describer is implemented by the Mutator that can describe the code they
+// mutate. It is used by the reports which show the mutation without the
+// diff.
+type Describer interface {
+	// Replacement returns the code put in place of the original one. It is
+	// empty if the original code is removed.
+	Replacement() string
+
+	// Location returns the start and the end token.Position of the original
+	// code replaced by the mutation.
+	Location() (token.Position, token.Position)
+}
@@ -60,6 +60,16 @@ func TestStatusString(t *testing.T) {
 			expected:       "TIMED OUT",
 			mutationStatus: mutator.TimedOut,
 		},
+		{
+			name:           "Ignored",
+			expected:       "IGNORED",
+			mutationStatus: mutator.Ignored,
+		},
+		{
+			name:           "Equivalent",
+			expected:       "EQUIVALENT",
+			mutationStatus: mutator.Equivalent,
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -132,6 +142,111 @@ func TestTypeString(t *testing.T) {
 			expected:   "REMOVE_SELF_ASSIGNMENTS",
 			mutantType: mutator.RemoveSelfAssignments,
 		},
+		{
+			name:       "RETURN_ZERO_VALUE",
+			expected:   "RETURN_ZERO_VALUE",
+			mutantType: mutator.ReturnZeroValue,
+		},
+		{
+			name:       "RETURN_NEGATE_BOOL",
+			expected:   "RETURN_NEGATE_BOOL",
+			mutantType: mutator.ReturnNegateBool,
+		},
+		{
+			name:       "RETURN_NIL_ERROR",
+			expected:   "RETURN_NIL_ERROR",
+			mutantType: mutator.ReturnNilError,
+		},
+		{
+			name:       "RETURN_ERROR",
+			expected:   "RETURN_ERROR",
+			mutantType: mutator.ReturnError,
+		},
+		{
+			name:       "RETURN_EMPTY_COLLECTION",
+			expected:   "RETURN_EMPTY_COLLECTION",
+			mutantType: mutator.ReturnEmptyCollection,
+		},
+		{
+			name:       "REMOVE_ERROR_CHECK",
+			expected:   "REMOVE_ERROR_CHECK",
+			mutantType: mutator.RemoveErrorCheck,
+		},
+		{
+			name:       "SWALLOW_ERROR",
+			expected:   "SWALLOW_ERROR",
+			mutantType: mutator.SwallowError,
+		},
+		{
+			name:       "UNWRAP_ERROR",
+			expected:   "UNWRAP_ERROR",
+			mutantType: mutator.UnwrapError,
+		},
+		{
+			name:       "CONDITION_TRUE",
+			expected:   "CONDITION_TRUE",
+			mutantType: mutator.ConditionTrue,
+		},
+		{
+			name:       "CONDITION_FALSE",
+			expected:   "CONDITION_FALSE",
+			mutantType: mutator.ConditionFalse,
+		},
+		{
+			name:       "NUMERIC_LITERAL",
+			expected:   "NUMERIC_LITERAL",
+			mutantType: mutator.NumericLiteral,
+		},
+		{
+			name:       "STRING_LITERAL",
+			expected:   "STRING_LITERAL",
+			mutantType: mutator.StringLiteral,
+		},
+		{
+			name:       "BOOLEAN_LITERAL",
+			expected:   "BOOLEAN_LITERAL",
+			mutantType: mutator.BooleanLiteral,
+		},
+		{
+			name:       "CALL_ZERO_VALUE",
+			expected:   "CALL_ZERO_VALUE",
+			mutantType: mutator.CallZeroValue,
+		},
+		{
+			name:       "SWAP_ARGUMENTS",
+			expected:   "SWAP_ARGUMENTS",
+			mutantType: mutator.SwapArguments,
+		},
+		{
+			name:       "DROP_VARIADIC",
+			expected:   "DROP_VARIADIC",
+			mutantType: mutator.DropVariadic,
+		},
+		{
+			name:       "REMOVE_LOCK",
+			expected:   "REMOVE_LOCK",
+			mutantType: mutator.RemoveLock,
+		},
+		{
+			name:       "SYNC_GOROUTINE",
+			expected:   "SYNC_GOROUTINE",
+			mutantType: mutator.SyncGoroutine,
+		},
+		{
+			name:       "CHANNEL_CAPACITY",
+			expected:   "CHANNEL_CAPACITY",
+			mutantType: mutator.ChannelCapacity,
+		},
+		{
+			name:       "REMOVE_CLOSE",
+			expected:   "REMOVE_CLOSE",
+			mutantType: mutator.RemoveClose,
+		},
+		{
+			name:       "REMOVE_WAITGROUP",
+			expected:   "REMOVE_WAITGROUP",
+			mutantType: mutator.RemoveWaitGroup,
+		},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -142,3 +257,51 @@ func TestTypeString(t *testing.T) {
 		})
 	}
 }
+
+func TestRegister(t *testing.T) {
+	mt := mutator.Register("REGISTERED_TYPE")
+
+	if mt.String() != "REGISTERED_TYPE" {
+		t.Errorf(cmp.Diff(mt.String(), "REGISTERED_TYPE"))
+	}
+	if got := mutator.Types[len(mutator.Types)-1]; got != mt {
+		t.Errorf("expected %s to be the last of the types, got %s", mt, got)
+	}
+
+	testCases := []struct {
+		name     string
+		typeName string
+	}{
+		{
+			name:     "lower case",
+			typeName: "registered_lower",
+		},
+		{
+			name:     "dash",
+			typeName: "REGISTERED-DASH",
+		},
+		{
+			name:     "empty",
+			typeName: "",
+		},
+		{
+			name:     "builtin",
+			typeName: "ARITHMETIC_BASE",
+		},
+		{
+			name:     "already registered",
+			typeName: "REGISTERED_TYPE",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected %q to panic", tc.typeName)
+				}
+			}()
+			mutator.Register(tc.typeName)
+		})
+	}
+}
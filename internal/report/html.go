@@ -0,0 +1,297 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package report
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+//go:embed templates/*.gohtml
+var htmlTemplates embed.FS
+
+const htmlIndex = "index.html"
+
+type htmlStats struct {
+	Total      int
+	Killed     int
+	Lived      int
+	NotCovered int
+	TimedOut   int
+	NotViable  int
+	Skipped    int
+	Ignored    int
+	Equivalent int
+	Runnable   int
+	Efficacy   float64
+	Coverage   float64
+}
+
+func (s *htmlStats) add(st mutator.Status) {
+	s.Total++
+	switch st {
+	case mutator.Killed:
+		s.Killed++
+	case mutator.Lived:
+		s.Lived++
+	case mutator.NotCovered:
+		s.NotCovered++
+	case mutator.TimedOut:
+		s.TimedOut++
+	case mutator.NotViable:
+		s.NotViable++
+	case mutator.Skipped:
+		s.Skipped++
+	case mutator.Ignored:
+		s.Ignored++
+	case mutator.Equivalent:
+		s.Equivalent++
+	case mutator.Runnable:
+		s.Runnable++
+	}
+}
+
+// compute calculates efficacy and coverage the same way the terminal
+// report does.
+func (s *htmlStats) compute(dryRun bool) {
+	if dryRun {
+		if s.Runnable > 0 {
+			s.Coverage = float64(s.Runnable) / float64(s.Runnable+s.NotCovered) * 100
+		}
+
+		return
+	}
+	if s.Killed > 0 {
+		s.Efficacy = float64(s.Killed) / float64(s.Killed+s.Lived) * 100
+	}
+	if s.Killed+s.Lived > 0 {
+		s.Coverage = float64(s.Killed+s.Lived) / float64(s.Killed+s.Lived+s.NotCovered) * 100
+	}
+}
+
+type htmlMutant struct {
+	Type   string
+	Status string
+	Class  string
+	Diff   string
+	Line   int
+	Column int
+}
+
+type htmlLine struct {
+	Code    string
+	Class   string
+	Mutants []htmlMutant
+	Number  int
+}
+
+type htmlFile struct {
+	Name    string
+	Page    string
+	Package *htmlPackage
+	Error   string
+	Lines   []htmlLine
+	mutants []mutator.Mutator
+	Stats   htmlStats
+}
+
+type htmlPackage struct {
+	Name  string
+	Page  string
+	Files []*htmlFile
+	Stats htmlStats
+}
+
+type htmlModule struct {
+	Name     string
+	Elapsed  string
+	Packages []*htmlPackage
+	Stats    htmlStats
+	DryRun   bool
+}
+
+// htmlReport writes a static site with the summary of the module, a page
+// for each package and a page for each file, with the source annotated
+// with the mutants.
+func (r *reportStatus) htmlReport(dir string, results Results) error {
+	tmpl, err := template.New("").Funcs(template.FuncMap{
+		"percent": func(f float64) string { return fmt.Sprintf("%.2f%%", f) },
+	}).ParseFS(htmlTemplates, "templates/*.gohtml")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	module := r.htmlModule(results)
+	if err := writeHTML(tmpl, filepath.Join(dir, htmlIndex), "index", module); err != nil {
+		return err
+	}
+	for _, p := range module.Packages {
+		if err := writeHTML(tmpl, filepath.Join(dir, p.Page), "package", p); err != nil {
+			return err
+		}
+		for _, f := range p.Files {
+			f.annotate(results.SrcDir)
+			if err := writeHTML(tmpl, filepath.Join(dir, f.Page), "file", f); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *reportStatus) htmlModule(results Results) *htmlModule {
+	dryRun := r.isDryRun()
+	module := &htmlModule{
+		Name:    results.Module,
+		Elapsed: r.elapsed.String(),
+		DryRun:  dryRun,
+	}
+	pkgs := make(map[string]*htmlPackage)
+	files := make(map[string]*htmlFile)
+	for _, m := range results.Mutants {
+		pkg, ok := pkgs[m.Pkg()]
+		if !ok {
+			pkg = &htmlPackage{Name: m.Pkg(), Page: pageName("pkg", m.Pkg())}
+			pkgs[m.Pkg()] = pkg
+			module.Packages = append(module.Packages, pkg)
+		}
+		fileName := m.Position().Filename
+		f, ok := files[fileName]
+		if !ok {
+			f = &htmlFile{Name: fileName, Page: pageName("file", fileName), Package: pkg}
+			files[fileName] = f
+			pkg.Files = append(pkg.Files, f)
+		}
+		f.mutants = append(f.mutants, m)
+		f.Stats.add(m.Status())
+		pkg.Stats.add(m.Status())
+		module.Stats.add(m.Status())
+	}
+
+	sort.Slice(module.Packages, func(i, j int) bool {
+		return module.Packages[i].Name < module.Packages[j].Name
+	})
+	for _, p := range module.Packages {
+		sort.Slice(p.Files, func(i, j int) bool {
+			return p.Files[i].Name < p.Files[j].Name
+		})
+		for _, f := range p.Files {
+			f.Stats.compute(dryRun)
+		}
+		p.Stats.compute(dryRun)
+	}
+	module.Stats.compute(dryRun)
+
+	return module
+}
+
+// annotate reads the source of the file and attaches the mutants to the
+// lines they are on. If the source can't be read, only the mutants are
+// shown.
+func (f *htmlFile) annotate(srcDir string) {
+	byLine := make(map[int][]htmlMutant)
+	for _, m := range f.mutants {
+		pos := m.Position()
+		byLine[pos.Line] = append(byLine[pos.Line], htmlMutant{
+			Type:   m.Type().String(),
+			Status: m.Status().String(),
+			Class:  statusClass(m.Status()),
+			Diff:   m.Diff(),
+			Line:   pos.Line,
+			Column: pos.Column,
+		})
+	}
+	for _, ms := range byLine {
+		sort.SliceStable(ms, func(i, j int) bool {
+			return ms[i].Column < ms[j].Column
+		})
+	}
+
+	src, err := os.ReadFile(filepath.Join(srcDir, f.Name))
+	if err != nil {
+		f.Error = fmt.Sprintf("impossible to read the source: %s", err)
+		lines := make([]int, 0, len(byLine))
+		for l := range byLine {
+			lines = append(lines, l)
+		}
+		sort.Ints(lines)
+		for _, l := range lines {
+			f.Lines = append(f.Lines, htmlLine{Number: l, Mutants: byLine[l], Class: lineClass(byLine[l])})
+		}
+
+		return
+	}
+	for i, code := range strings.Split(strings.TrimSuffix(string(src), "\n"), "\n") {
+		n := i + 1
+		f.Lines = append(f.Lines, htmlLine{
+			Number:  n,
+			Code:    code,
+			Mutants: byLine[n],
+			Class:   lineClass(byLine[n]),
+		})
+	}
+}
+
+// lineClass returns the class of the most relevant status on the line:
+// a LIVED mutant is more interesting than a KILLED one.
+func lineClass(ms []htmlMutant) string {
+	priority := []string{"lived", "not-covered", "timed-out", "killed", "runnable", "equivalent", "not-viable", "skipped", "ignored"}
+	for _, p := range priority {
+		for _, m := range ms {
+			if m.Class == p {
+				return p
+			}
+		}
+	}
+
+	return ""
+}
+
+func statusClass(s mutator.Status) string {
+	return strings.ReplaceAll(strings.ToLower(s.String()), " ", "-")
+}
+
+// pageName returns a stable, flat file name for the page of a package or
+// file, so that all the pages can link each other with relative links.
+func pageName(kind, name string) string {
+	sum := sha256.Sum256([]byte(name))
+
+	return fmt.Sprintf("%s-%s.html", kind, hex.EncodeToString(sum[:])[:12])
+}
+
+func writeHTML(tmpl *template.Template, path, name string, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
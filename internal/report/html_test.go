@@ -0,0 +1,120 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package report_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+	"github.com/singhnishant94/gremlins/internal/report"
+)
+
+const htmlSource = `package pkg
+
+func Gtr(a, b int) bool {
+	return a > b && b < 10
+}
+`
+
+func TestReportToHTML(t *testing.T) {
+	srcDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(srcDir, "pkg"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "pkg", "file1.go"), []byte(htmlSource), 0600); err != nil {
+		t.Fatal(err)
+	}
+	data := report.Results{
+		Module: "example.com",
+		SrcDir: srcDir,
+		Mutants: []mutator.Mutator{
+			stubMutant{status: mutator.Lived, mutantType: mutator.ConditionalsBoundary, position: newPosition("pkg/file1.go", 11, 4),
+				pkg: "example.com/pkg", diff: "-\treturn a > b && b < 10\n+\treturn a >= b && b < 10"},
+			stubMutant{status: mutator.Killed, mutantType: mutator.ConditionalsNegation, position: newPosition("pkg/file1.go", 11, 4),
+				pkg: "example.com/pkg"},
+			stubMutant{status: mutator.NotCovered, mutantType: mutator.ConditionalsBoundary, position: newPosition("missing.go", 3, 2),
+				pkg: "example.com"},
+		},
+		Elapsed: 2 * time.Second,
+	}
+	outDir := filepath.Join(t.TempDir(), "html")
+	viper.Set(configuration.UnleashOutputHTMLKey, outDir)
+	defer viper.Reset()
+
+	if err := report.Do(data); err != nil {
+		t.Fatal("error not expected")
+	}
+
+	index := readPage(t, filepath.Join(outDir, "index.html"))
+	for _, want := range []string{"<h1>example.com</h1>", ">example.com/pkg</a>", ">example.com</a>", "<td>50.00%</td>"} {
+		if !strings.Contains(index, want) {
+			t.Errorf("expected index to contain %q", want)
+		}
+	}
+
+	pkgPage := filepath.Join(outDir, linkTo(t, index, "example.com/pkg"))
+	pkg := readPage(t, pkgPage)
+	if !strings.Contains(pkg, ">pkg/file1.go</a>") {
+		t.Errorf("expected package page to link the file")
+	}
+
+	file := readPage(t, filepath.Join(outDir, linkTo(t, pkg, "pkg/file1.go")))
+	for _, want := range []string{
+		`<tr id="L4" class="lived">`,
+		"return a &gt; b &amp;&amp; b &lt; 10",
+		`<span class="badge lived">LIVED</span> CONDITIONALS_BOUNDARY at 4:11`,
+		`<span class="badge killed">KILLED</span> CONDITIONALS_NEGATION at 4:11`,
+		"&#43;\treturn a &gt;= b &amp;&amp; b &lt; 10",
+	} {
+		if !strings.Contains(file, want) {
+			t.Errorf("expected file page to contain %q", want)
+		}
+	}
+
+	missing := readPage(t, filepath.Join(outDir, linkTo(t, readPage(t, filepath.Join(outDir, linkTo(t, index, "example.com"))), "missing.go")))
+	if !strings.Contains(missing, "impossible to read the source") || !strings.Contains(missing, "NOT COVERED") {
+		t.Errorf("expected missing file page to report the error and the mutants")
+	}
+}
+
+func readPage(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected page %s to be written: %s", path, err)
+	}
+
+	return string(data)
+}
+
+func linkTo(t *testing.T, page, name string) string {
+	t.Helper()
+	m := regexp.MustCompile(`<a href="([^"]+)">` + regexp.QuoteMeta(name) + `</a>`).FindStringSubmatch(page)
+	if m == nil {
+		t.Fatalf("expected a link to %q", name)
+	}
+
+	return m[1]
+}
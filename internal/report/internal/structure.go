@@ -26,7 +26,10 @@ type OutputResult struct {
 	MutantsKilled     int          `json:"mutants_killed"`
 	MutantsLived      int          `json:"mutants_lived"`
 	MutantsNotViable  int          `json:"mutants_not_viable"`
+	MutantsIgnored    int          `json:"mutants_ignored,omitempty"`
+	MutantsEquivalent int          `json:"mutants_equivalent,omitempty"`
 	MutantsNotCovered int          `json:"mutants_not_covered"`
+	MutantsCached     int          `json:"mutants_cached,omitempty"`
 	ElapsedTime       float64      `json:"elapsed_time"`
 	MutatorStatistics MutatorType  `json:"mutator_statistics"`
 }
@@ -61,4 +64,8 @@ type MutatorType struct {
 	RemoveBinaryExpressionLeft  int `json:"remove_binary_expression_left,omitempty"`
 	RemoveBinaryExpressionRight int `json:"remove_binary_expression_right,omitempty"`
 	RemoveStatement             int `json:"remove_statement"`
+
+	// Others counts the mutants of the types without a field, such as the
+	// registered ones, by the lowercase name of their type.
+	Others map[string]int `json:"others,omitempty"`
 }
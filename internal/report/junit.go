@@ -0,0 +1,151 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+
+	// names counts the testcases with the same name, to tell them apart.
+	names map[string]int
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// junitReport writes the results as a JUnit XML report. Each package is a
+// testsuite and each mutant is a testcase: LIVED mutants are failures,
+// NOT VIABLE mutants are errors and SKIPPED and NOT COVERED mutants are
+// skipped. KILLED and TIMED OUT mutants are reported as passed.
+func (r *reportStatus) junitReport(output string, results Results) error {
+	suites := make(map[string]*junitTestSuite)
+	for _, m := range results.Mutants {
+		suite, ok := suites[m.Pkg()]
+		if !ok {
+			suite = &junitTestSuite{Name: m.Pkg(), names: make(map[string]int)}
+			suites[m.Pkg()] = suite
+		}
+		suite.add(newJUnitTestCase(m))
+	}
+
+	rep := junitTestSuites{
+		Name: results.Module,
+		Time: fmt.Sprintf("%.3f", r.elapsed.Duration().Seconds()),
+	}
+	names := make([]string, 0, len(suites))
+	for name := range suites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := suites[name]
+		rep.Tests += s.Tests
+		rep.Failures += s.Failures
+		rep.Errors += s.Errors
+		rep.Skipped += s.Skipped
+		rep.Suites = append(rep.Suites, *s)
+	}
+
+	data, err := xml.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append([]byte(xml.Header), data...)
+
+	return os.WriteFile(output, data, 0600)
+}
+
+// add appends the testcase to the suite. If another testcase has the same
+// name, like the mutants of the same type at the same position without a
+// known replacement, the name is suffixed with its index.
+func (s *junitTestSuite) add(tc junitTestCase) {
+	s.names[tc.Name]++
+	if n := s.names[tc.Name]; n > 1 {
+		tc.Name = fmt.Sprintf("%s #%d", tc.Name, n)
+	}
+	s.Tests++
+	switch {
+	case tc.Failure != nil:
+		s.Failures++
+	case tc.Error != nil:
+		s.Errors++
+	case tc.Skipped != nil:
+		s.Skipped++
+	}
+	s.TestCases = append(s.TestCases, tc)
+}
+
+func newJUnitTestCase(m mutator.Mutator) junitTestCase {
+	pos := m.Position()
+	tc := junitTestCase{
+		Name:      fmt.Sprintf("%s at %s:%d:%d", m.Type(), pos.Filename, pos.Line, pos.Column),
+		ClassName: m.Pkg(),
+		File:      pos.Filename,
+		Line:      pos.Line,
+	}
+	if d, ok := m.(mutator.Describer); ok && d.Replacement() != "" {
+		tc.Name += " with " + strings.Join(strings.Fields(d.Replacement()), " ")
+	}
+	switch m.Status() {
+	case mutator.Lived:
+		tc.Failure = &junitMessage{Message: "the mutant survived", Type: m.Status().String(), Body: m.Diff()}
+	case mutator.NotViable:
+		tc.Error = &junitMessage{Message: "the mutant doesn't compile", Type: m.Status().String(), Body: m.Diff()}
+	case mutator.Skipped, mutator.Ignored, mutator.Equivalent, mutator.NotCovered, mutator.Runnable:
+		tc.Skipped = &junitMessage{Message: m.Status().String()}
+	}
+
+	return tc
+}
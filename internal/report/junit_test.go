@@ -0,0 +1,100 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package report_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+	"github.com/singhnishant94/gremlins/internal/report"
+)
+
+func TestReportToJUnit(t *testing.T) {
+	data := report.Results{
+		Module: "example.com",
+		Mutants: []mutator.Mutator{
+			describedMutant{
+				stubMutant: stubMutant{status: mutator.Lived, mutantType: mutator.ConditionalsBoundary, position: newPosition("pkg/file1.go", 11, 4),
+					pkg: "example.com/pkg", diff: "-a > b\n+a >= b"},
+				replacement: ">=",
+			},
+			describedMutant{
+				stubMutant: stubMutant{status: mutator.Killed, mutantType: mutator.ConditionalsBoundary, position: newPosition("pkg/file1.go", 11, 4),
+					pkg: "example.com/pkg"},
+				replacement: "<=",
+			},
+			stubMutant{status: mutator.Killed, mutantType: mutator.ConditionalsNegation, position: newPosition("pkg/file1.go", 11, 4),
+				pkg: "example.com/pkg"},
+			stubMutant{status: mutator.NotViable, mutantType: mutator.ArithmeticBase, position: newPosition("file2.go", 3, 2),
+				pkg: "example.com"},
+			stubMutant{status: mutator.NotCovered, mutantType: mutator.ArithmeticBase, position: newPosition("file2.go", 3, 5),
+				pkg: "example.com"},
+			stubMutant{status: mutator.Skipped, mutantType: mutator.ArithmeticBase, position: newPosition("file2.go", 3, 6),
+				pkg: "example.com"},
+			stubMutant{status: mutator.TimedOut, mutantType: mutator.ArithmeticBase, position: newPosition("file2.go", 3, 7),
+				pkg: "example.com"},
+			stubMutant{status: mutator.Killed, mutantType: mutator.ArithmeticBase, position: newPosition("file2.go", 3, 7),
+				pkg: "example.com"},
+		},
+		Elapsed: 2 * time.Second,
+	}
+	output := filepath.Join(t.TempDir(), "gremlins.xml")
+	viper.Set(configuration.UnleashOutputJUnitKey, output)
+	defer viper.Reset()
+
+	if err := report.Do(data); err != nil {
+		t.Fatal("error not expected")
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="example.com" tests="8" failures="1" errors="1" skipped="2" time="2.000">
+  <testsuite name="example.com" tests="5" failures="0" errors="1" skipped="2">
+    <testcase name="ARITHMETIC_BASE at file2.go:2:3" classname="example.com" file="file2.go" line="2">
+      <error message="the mutant doesn&#39;t compile" type="NOT VIABLE"></error>
+    </testcase>
+    <testcase name="ARITHMETIC_BASE at file2.go:5:3" classname="example.com" file="file2.go" line="5">
+      <skipped message="NOT COVERED"></skipped>
+    </testcase>
+    <testcase name="ARITHMETIC_BASE at file2.go:6:3" classname="example.com" file="file2.go" line="6">
+      <skipped message="SKIPPED"></skipped>
+    </testcase>
+    <testcase name="ARITHMETIC_BASE at file2.go:7:3" classname="example.com" file="file2.go" line="7"></testcase>
+    <testcase name="ARITHMETIC_BASE at file2.go:7:3 #2" classname="example.com" file="file2.go" line="7"></testcase>
+  </testsuite>
+  <testsuite name="example.com/pkg" tests="3" failures="1" errors="0" skipped="0">
+    <testcase name="CONDITIONALS_BOUNDARY at pkg/file1.go:4:11 with &gt;=" classname="example.com/pkg" file="pkg/file1.go" line="4">
+      <failure message="the mutant survived" type="LIVED">-a &gt; b&#xA;+a &gt;= b</failure>
+    </testcase>
+    <testcase name="CONDITIONALS_BOUNDARY at pkg/file1.go:4:11 with &lt;=" classname="example.com/pkg" file="pkg/file1.go" line="4"></testcase>
+    <testcase name="CONDITIONALS_NEGATION at pkg/file1.go:4:11" classname="example.com/pkg" file="pkg/file1.go" line="4"></testcase>
+  </testsuite>
+</testsuites>`
+	if !cmp.Equal(string(got), want) {
+		t.Error(cmp.Diff(want, string(got)))
+	}
+}
@@ -10,7 +10,7 @@ import (
 
 type Filter = map[mutator.Status]struct{}
 
-var ErrInvalidFilter = errors.New("invalid statuses filter, only 'lctkvsr' letters allowed")
+var ErrInvalidFilter = errors.New("invalid statuses filter, only 'lctkvsrie' letters allowed")
 
 // MutantLogger prints mutant statuses based on filter and verbosity flags.
 type MutantLogger struct {
@@ -64,6 +64,10 @@ func ParseFilter(s string) (Filter, error) {
 			result[mutator.Skipped] = struct{}{}
 		case 'r':
 			result[mutator.Runnable] = struct{}{}
+		case 'i':
+			result[mutator.Ignored] = struct{}{}
+		case 'e':
+			result[mutator.Equivalent] = struct{}{}
 		default:
 			return nil, ErrInvalidFilter
 		}
@@ -42,6 +42,18 @@ func Test_parseFilter(t *testing.T) {
 				mutator.Runnable: struct{}{},
 			},
 		},
+		{
+			filter: "i",
+			want: report.Filter{
+				mutator.Ignored: struct{}{},
+			},
+		},
+		{
+			filter: "e",
+			want: report.Filter{
+				mutator.Equivalent: struct{}{},
+			},
+		},
 		{
 			filter: "",
 		},
@@ -99,7 +111,8 @@ func TestLogger(t *testing.T) {
 	want := "output-statuses filter not applied: " + report.ErrInvalidFilter.Error() + "\n" +
 		" NOT COVERED CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n" +
 		"      KILLED CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n" +
-		"       LIVED CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n"
+		"       LIVED CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n" +
+		"\n"
 
 	if !cmp.Equal(got, want) {
 		t.Errorf(cmp.Diff(got, want))
@@ -19,6 +19,8 @@ package report
 import (
 	"encoding/json"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -46,10 +48,15 @@ type Results struct {
 	Module  string
 	Mutants []mutator.Mutator
 	Elapsed time.Duration
+	// SrcDir is the directory the file names of the Mutants are relative to.
+	SrcDir string
+	// Cached is the number of Mutants whose status comes from the cache.
+	Cached int
 }
 
 type reportStatus struct {
-	files map[string][]internal.Mutation
+	files   map[string][]internal.Mutation
+	results Results
 
 	elapsed *durafmt.Durafmt
 	module  string
@@ -61,6 +68,9 @@ type reportStatus struct {
 	skipped    int
 	notViable  int
 	runnable   int
+	equivalent int
+	cached     int
+	ignored    map[string]int
 
 	mutatorStatistics internal.MutatorType
 
@@ -76,6 +86,8 @@ func newReport(results Results) (*reportStatus, bool) {
 	rep := &reportStatus{
 		module:  results.Module,
 		elapsed: durafmt.Parse(results.Elapsed).LimitFirstN(2),
+		cached:  results.Cached,
+		results: results,
 	}
 	rep.files = make(map[string][]internal.Mutation)
 	for _, m := range results.Mutants {
@@ -119,6 +131,13 @@ func reportMutationStatus(m mutator.Mutator, rep *reportStatus) {
 		rep.notViable++
 	case mutator.Runnable:
 		rep.runnable++
+	case mutator.Equivalent:
+		rep.equivalent++
+	case mutator.Ignored:
+		if rep.ignored == nil {
+			rep.ignored = make(map[string]int)
+		}
+		rep.ignored[m.Position().Filename]++
 	}
 }
 
@@ -146,6 +165,17 @@ func reportMutatorType(m mutator.Mutator, rep *reportStatus) {
 		rep.mutatorStatistics.InvertNegatives++
 	case mutator.RemoveSelfAssignments:
 		rep.mutatorStatistics.RemoveSelfAssignments++
+	case mutator.RemoveBinaryExpressionLeft:
+		rep.mutatorStatistics.RemoveBinaryExpressionLeft++
+	case mutator.RemoveBinaryExpressionRight:
+		rep.mutatorStatistics.RemoveBinaryExpressionRight++
+	case mutator.RemoveStatement:
+		rep.mutatorStatistics.RemoveStatement++
+	default:
+		if rep.mutatorStatistics.Others == nil {
+			rep.mutatorStatistics.Others = make(map[string]int)
+		}
+		rep.mutatorStatistics.Others[strings.ToLower(m.Type().String())]++
 	}
 }
 
@@ -160,6 +190,50 @@ func (r *reportStatus) reportFindings() {
 		r.fullRunReport()
 	}
 	r.fileReport()
+	r.htmlFileReport()
+	r.strykerFileReport()
+	r.sarifFileReport()
+	r.junitFileReport()
+}
+
+func (r *reportStatus) junitFileReport() {
+	output := configuration.Get[string](configuration.UnleashOutputJUnitKey)
+	if output == "" {
+		return
+	}
+	if err := r.junitReport(output, r.results); err != nil {
+		log.Errorf("impossible to write JUnit report: %s\n", err)
+	}
+}
+
+func (r *reportStatus) sarifFileReport() {
+	output := configuration.Get[string](configuration.UnleashOutputSARIFKey)
+	if output == "" {
+		return
+	}
+	if err := r.sarifReport(output, r.results); err != nil {
+		log.Errorf("impossible to write SARIF report: %s\n", err)
+	}
+}
+
+func (r *reportStatus) strykerFileReport() {
+	output := configuration.Get[string](configuration.UnleashOutputStrykerKey)
+	if output == "" {
+		return
+	}
+	if err := r.strykerReport(output, r.results); err != nil {
+		log.Errorf("impossible to write Stryker report: %s\n", err)
+	}
+}
+
+func (r *reportStatus) htmlFileReport() {
+	dir := configuration.Get[string](configuration.UnleashOutputHTMLKey)
+	if dir == "" {
+		return
+	}
+	if err := r.htmlReport(dir, r.results); err != nil {
+		log.Errorf("impossible to write HTML report: %s\n", err)
+	}
 }
 
 func (r *reportStatus) fileReport() {
@@ -180,6 +254,9 @@ func (r *reportStatus) fileReport() {
 			MutantsLived:      r.lived,
 			MutantsNotViable:  r.notViable,
 			MutantsNotCovered: r.notCovered,
+			MutantsIgnored:    r.ignoredCount(),
+			MutantsEquivalent: r.equivalent,
+			MutantsCached:     r.cached,
 			ElapsedTime:       r.elapsed.Duration().Seconds(),
 			MutatorStatistics: r.mutatorStatistics,
 			Files:             files,
@@ -206,6 +283,7 @@ func (r *reportStatus) dryRunReport() {
 	log.Infoln("")
 	log.Infof("Dry run completed in %s\n", r.elapsed.String())
 	log.Infof("Runnable: %s, Not covered: %s\n", runnable, notCovered)
+	r.ignoredReport()
 	log.Infof("Mutator coverage: %.2f%%\n", r.mCovered)
 }
 
@@ -220,10 +298,43 @@ func (r *reportStatus) fullRunReport() {
 	log.Infof("Mutation testing completed in %s\n", r.elapsed.String())
 	log.Infof("Killed: %s, Lived: %s, Not covered: %s\n", killed, lived, notCovered)
 	log.Infof("Timed out: %s, Not viable: %s, Skipped: %s\n", timedOut, notViable, skipped)
+	if r.equivalent > 0 {
+		log.Infof("Equivalent: %s\n", fgHiBlack(r.equivalent))
+	}
+	r.ignoredReport()
+	if r.cached > 0 {
+		log.Infof("From cache: %d\n", r.cached)
+	}
 	log.Infof("Test efficacy: %.2f%%\n", r.tEfficacy)
 	log.Infof("Mutator coverage: %.2f%%\n", r.mCovered)
 }
 
+// ignoredReport lists how many mutants are suppressed by the ignore
+// comments of each file, so that the suppressions can be reviewed.
+func (r *reportStatus) ignoredReport() {
+	if len(r.ignored) == 0 {
+		return
+	}
+	log.Infof("Ignored: %s\n", fgHiBlack(r.ignoredCount()))
+	files := make([]string, 0, len(r.ignored))
+	for f := range r.ignored {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		log.Infof("  %s: %d\n", f, r.ignored[f])
+	}
+}
+
+func (r *reportStatus) ignoredCount() int {
+	count := 0
+	for _, n := range r.ignored {
+		count += n
+	}
+
+	return count
+}
+
 func (r *reportStatus) assess(tEfficacy, rCoverage float64) error {
 	if r.isDryRun() {
 		return nil
@@ -280,9 +391,11 @@ func Mutant(m mutator.Mutator) {
 		status = fgHiYellow(m.Status())
 	case mutator.TimedOut:
 		status = fgGreen(m.Status())
-	case mutator.NotViable, mutator.Skipped:
+	case mutator.NotViable, mutator.Skipped, mutator.Ignored:
 		status = fgHiBlack(m.Status())
 		shouldLog = false
+	case mutator.Equivalent:
+		status = fgHiBlack(m.Status())
 	}
 	if shouldLog {
 		log.Infof("%s%s %s at %s\n", padding(m.Status()), status, m.Type(), m.Position())
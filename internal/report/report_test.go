@@ -53,6 +53,7 @@ func TestReport(t *testing.T) {
 		name    string
 		mutants []mutator.Mutator
 		want    string
+		cached  int
 	}{
 		{
 			name: "reports findings in normal run",
@@ -99,6 +100,57 @@ func TestReport(t *testing.T) {
 				"Test efficacy: 0.00%\n" +
 				coverageLine,
 		},
+		{
+			name: "reports findings from the cache",
+			mutants: []mutator.Mutator{
+				stubMutant{status: mutator.Lived, mutantType: mutator.ConditionalsNegation, position: fakePosition},
+				stubMutant{status: mutator.Killed, mutantType: mutator.ConditionalsNegation, position: fakePosition},
+			},
+			cached: 1,
+			want: "\n" +
+				// Limit the time reporting to the first two units (millis are excluded)
+				testingLine +
+				"Killed: 1, Lived: 1, Not covered: 0\n" +
+				"Timed out: 0, Not viable: 0, Skipped: 0\n" +
+				"From cache: 1\n" +
+				"Test efficacy: 50.00%\n" +
+				"Mutator coverage: 100.00%\n",
+		},
+		{
+			name: "reports findings with ignored mutants by file",
+			mutants: []mutator.Mutator{
+				stubMutant{status: mutator.Killed, mutantType: mutator.ConditionalsNegation, position: fakePosition},
+				stubMutant{status: mutator.Ignored, mutantType: mutator.ConditionalsNegation, position: newPosition("b.go", 3, 10)},
+				stubMutant{status: mutator.Ignored, mutantType: mutator.ConditionalsBoundary, position: newPosition("a.go", 3, 10)},
+				stubMutant{status: mutator.Ignored, mutantType: mutator.ConditionalsNegation, position: newPosition("a.go", 3, 10)},
+			},
+			want: "\n" +
+				// Limit the time reporting to the first two units (millis are excluded)
+				testingLine +
+				"Killed: 1, Lived: 0, Not covered: 0\n" +
+				"Timed out: 0, Not viable: 0, Skipped: 0\n" +
+				"Ignored: 3\n" +
+				"  a.go: 2\n" +
+				"  b.go: 1\n" +
+				"Test efficacy: 100.00%\n" +
+				"Mutator coverage: 100.00%\n",
+		},
+		{
+			name: "reports findings with equivalent mutants",
+			mutants: []mutator.Mutator{
+				stubMutant{status: mutator.Killed, mutantType: mutator.ConditionalsNegation, position: fakePosition},
+				stubMutant{status: mutator.Lived, mutantType: mutator.ConditionalsNegation, position: fakePosition},
+				stubMutant{status: mutator.Equivalent, mutantType: mutator.InvertAssignments, position: fakePosition},
+			},
+			want: "\n" +
+				// Limit the time reporting to the first two units (millis are excluded)
+				testingLine +
+				"Killed: 1, Lived: 1, Not covered: 0\n" +
+				"Timed out: 0, Not viable: 0, Skipped: 0\n" +
+				"Equivalent: 1\n" +
+				"Test efficacy: 50.00%\n" +
+				"Mutator coverage: 100.00%\n",
+		},
 		{
 			name:    "reports nothing if no result",
 			mutants: []mutator.Mutator{},
@@ -115,6 +167,7 @@ func TestReport(t *testing.T) {
 			data := report.Results{
 				Mutants: tc.mutants,
 				Elapsed: (2 * time.Minute) + (22 * time.Second) + (123 * time.Millisecond),
+				Cached:  tc.cached,
 			}
 
 			_ = report.Do(data)
@@ -316,14 +369,14 @@ func TestMutantLog(t *testing.T) {
 
 	got := out.String()
 
+	// LIVED mutants are followed by their diff, NOT VIABLE and SKIPPED are not logged.
 	want := "" +
 		"       LIVED CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n" +
+		"\n" +
 		"      KILLED CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n" +
 		" NOT COVERED CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n" +
 		"    RUNNABLE CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n" +
-		"  NOT VIABLE CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n" +
-		"   TIMED OUT CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n" +
-		"     SKIPPED CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n"
+		"   TIMED OUT CONDITIONALS_BOUNDARY at aFolder/aFile.go:12:3\n"
 
 	if !cmp.Equal(got, want) {
 		t.Errorf(cmp.Diff(got, want))
@@ -445,6 +498,8 @@ func sortMutation(x, y internal.Mutation) bool {
 
 type stubMutant struct {
 	position   token.Position
+	pkg        string
+	diff       string
 	status     mutator.Status
 	mutantType mutator.Type
 }
@@ -473,8 +528,8 @@ func (stubMutant) Pos() token.Pos {
 	return 123
 }
 
-func (stubMutant) Pkg() string {
-	panic("implement me")
+func (s stubMutant) Pkg() string {
+	return s.pkg
 }
 
 func (stubMutant) SetWorkdir(_ string) {
@@ -492,3 +547,19 @@ func (stubMutant) Apply() error {
 func (stubMutant) Rollback() error {
 	panic("implement me")
 }
+
+func (s stubMutant) Diff() string {
+	return s.diff
+}
+
+func (stubMutant) SetDiff(_ string) {
+	panic("implement me")
+}
+
+func (stubMutant) SetTestExecutionError(_ error) {
+	panic("implement me")
+}
+
+func (stubMutant) TestExecutionError() error {
+	panic("implement me")
+}
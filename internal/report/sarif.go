@@ -0,0 +1,187 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchema    = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifSrcRoot   = "SRCROOT"
+	gremlinsDocURI = "https://gremlins.dev"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// sarifReport writes a SARIF 2.1.0 log containing a result for each LIVED
+// and NOT COVERED mutant. The rules are the mutator.Type of the reported
+// mutants, and the locations are relative to the source directory.
+func (*reportStatus) sarifReport(output string, results Results) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gremlins",
+			InformationURI: gremlinsDocURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	if results.SrcDir != "" {
+		root := url.URL{Scheme: "file", Path: filepath.ToSlash(results.SrcDir) + "/"}
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{sarifSrcRoot: {URI: root.String()}}
+	}
+
+	rules := make(map[mutator.Type]int)
+	for _, m := range results.Mutants {
+		level, ok := sarifLevel(m.Status())
+		if !ok {
+			continue
+		}
+		idx, ok := rules[m.Type()]
+		if !ok {
+			idx = len(run.Tool.Driver.Rules)
+			rules[m.Type()] = idx
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               m.Type().String(),
+				ShortDescription: sarifMessage{Text: fmt.Sprintf("Mutant %s", m.Type())},
+			})
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    m.Type().String(),
+			RuleIndex: idx,
+			Level:     level,
+			Message:   sarifMessage{Text: sarifText(m)},
+			Locations: []sarifLocation{sarifMutantLocation(m, results.SrcDir != "")},
+		})
+	}
+
+	data, err := json.Marshal(sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, data, 0600)
+}
+
+// sarifLevel returns the level of the result for the given mutator.Status,
+// and false if mutants with the status are not reported.
+func sarifLevel(s mutator.Status) (string, bool) {
+	switch s {
+	case mutator.Lived:
+		return "warning", true
+	case mutator.NotCovered:
+		return "note", true
+	default:
+		return "", false
+	}
+}
+
+func sarifText(m mutator.Mutator) string {
+	var b strings.Builder
+	switch m.Status() {
+	case mutator.Lived:
+		_, _ = fmt.Fprintf(&b, "The %s mutant survived: no test failed when the code was mutated.", m.Type())
+	default:
+		_, _ = fmt.Fprintf(&b, "The %s mutant is not covered by any test.", m.Type())
+	}
+	if d := m.Diff(); d != "" {
+		_, _ = fmt.Fprintf(&b, "\n\n%s", d)
+	}
+
+	return b.String()
+}
+
+func sarifMutantLocation(m mutator.Mutator, relative bool) sarifLocation {
+	pos := m.Position()
+	region := sarifRegion{StartLine: pos.Line, StartColumn: pos.Column}
+	if d, ok := m.(mutator.Describer); ok {
+		start, end := d.Location()
+		region = sarifRegion{StartLine: start.Line, StartColumn: start.Column, EndLine: end.Line, EndColumn: end.Column}
+	}
+	artifact := sarifArtifactLocation{URI: filepath.ToSlash(pos.Filename)}
+	if relative {
+		artifact.URIBaseID = sarifSrcRoot
+	}
+
+	return sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact, Region: region}}
+}
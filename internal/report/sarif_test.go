@@ -0,0 +1,109 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package report_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+	"github.com/singhnishant94/gremlins/internal/report"
+)
+
+func TestReportToSARIF(t *testing.T) {
+	data := report.Results{
+		Module: "example.com",
+		SrcDir: "/src/example",
+		Mutants: []mutator.Mutator{
+			describedMutant{
+				stubMutant:  stubMutant{status: mutator.Lived, mutantType: mutator.ConditionalsBoundary, position: newPosition("pkg/file1.go", 11, 4), diff: "-a > b\n+a >= b"},
+				replacement: ">=",
+				start:       [2]int{4, 11},
+				end:         [2]int{4, 12},
+			},
+			stubMutant{status: mutator.Killed, mutantType: mutator.ConditionalsNegation, position: newPosition("pkg/file1.go", 11, 4)},
+			stubMutant{status: mutator.NotViable, mutantType: mutator.ArithmeticBase, position: newPosition("file2.go", 3, 2)},
+			stubMutant{status: mutator.NotCovered, mutantType: mutator.ArithmeticBase, position: newPosition("file2.go", 3, 2)},
+			stubMutant{status: mutator.Lived, mutantType: mutator.ConditionalsBoundary, position: newPosition("file2.go", 5, 7)},
+		},
+		Elapsed: 2 * time.Second,
+	}
+	output := filepath.Join(t.TempDir(), "gremlins.sarif")
+	viper.Set(configuration.UnleashOutputSARIFKey, output)
+	defer viper.Reset()
+
+	if err := report.Do(data); err != nil {
+		t.Fatal("error not expected")
+	}
+
+	file, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(file, &got); err != nil {
+		t.Fatal(err)
+	}
+	result := func(rule string, idx float64, level, text, uri string, region map[string]any) any {
+		return map[string]any{
+			"ruleId":    rule,
+			"ruleIndex": idx,
+			"level":     level,
+			"message":   map[string]any{"text": text},
+			"locations": []any{map[string]any{"physicalLocation": map[string]any{
+				"artifactLocation": map[string]any{"uri": uri, "uriBaseId": "SRCROOT"},
+				"region":           region,
+			}}},
+		}
+	}
+	want := map[string]any{
+		"version": "2.1.0",
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"runs": []any{map[string]any{
+			"tool": map[string]any{"driver": map[string]any{
+				"name":           "gremlins",
+				"informationUri": "https://gremlins.dev",
+				"rules": []any{
+					map[string]any{"id": "CONDITIONALS_BOUNDARY", "shortDescription": map[string]any{"text": "Mutant CONDITIONALS_BOUNDARY"}},
+					map[string]any{"id": "ARITHMETIC_BASE", "shortDescription": map[string]any{"text": "Mutant ARITHMETIC_BASE"}},
+				},
+			}},
+			"originalUriBaseIds": map[string]any{"SRCROOT": map[string]any{"uri": "file:///src/example/"}},
+			"results": []any{
+				result("CONDITIONALS_BOUNDARY", 0, "warning",
+					"The CONDITIONALS_BOUNDARY mutant survived: no test failed when the code was mutated.\n\n-a > b\n+a >= b",
+					"pkg/file1.go", map[string]any{"startLine": float64(4), "startColumn": float64(11), "endLine": float64(4), "endColumn": float64(12)}),
+				result("ARITHMETIC_BASE", 1, "note",
+					"The ARITHMETIC_BASE mutant is not covered by any test.",
+					"file2.go", map[string]any{"startLine": float64(2), "startColumn": float64(3)}),
+				result("CONDITIONALS_BOUNDARY", 0, "warning",
+					"The CONDITIONALS_BOUNDARY mutant survived: no test failed when the code was mutated.",
+					"file2.go", map[string]any{"startLine": float64(7), "startColumn": float64(5)}),
+			},
+		}},
+	}
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
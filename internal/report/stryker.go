@@ -0,0 +1,164 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/singhnishant94/gremlins/internal/mutator"
+)
+
+// strykerSchemaVersion is the version of the mutation-testing-report-schema
+// the report adheres to.
+const strykerSchemaVersion = "2"
+
+type strykerReport struct {
+	SchemaVersion string                 `json:"schemaVersion"`
+	Thresholds    strykerThresholds      `json:"thresholds"`
+	ProjectRoot   string                 `json:"projectRoot,omitempty"`
+	Files         map[string]strykerFile `json:"files"`
+	Framework     strykerFramework       `json:"framework"`
+}
+
+type strykerThresholds struct {
+	High int `json:"high"`
+	Low  int `json:"low"`
+}
+
+type strykerFramework struct {
+	Name string `json:"name"`
+}
+
+type strykerFile struct {
+	Language string          `json:"language"`
+	Source   string          `json:"source"`
+	Mutants  []strykerMutant `json:"mutants"`
+}
+
+type strykerMutant struct {
+	ID          string          `json:"id"`
+	MutatorName string          `json:"mutatorName"`
+	Replacement string          `json:"replacement"`
+	Location    strykerLocation `json:"location"`
+	Status      string          `json:"status"`
+}
+
+type strykerLocation struct {
+	Start strykerPosition `json:"start"`
+	End   strykerPosition `json:"end"`
+}
+
+type strykerPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// strykerStatus maps the mutator.Status to the mutant status of the schema.
+func strykerStatus(s mutator.Status) string {
+	switch s {
+	case mutator.Killed:
+		return "Killed"
+	case mutator.Lived:
+		return "Survived"
+	case mutator.NotCovered:
+		return "NoCoverage"
+	case mutator.NotViable:
+		return "CompileError"
+	case mutator.TimedOut:
+		return "Timeout"
+	case mutator.Skipped, mutator.Ignored, mutator.Equivalent:
+		return "Ignored"
+	default:
+		return "Pending"
+	}
+}
+
+// strykerReport writes the results in the JSON format defined by the
+// mutation-testing-report-schema, which can be read by the
+// mutation-testing-elements HTML report and the Stryker dashboard.
+func (r *reportStatus) strykerReport(output string, results Results) error {
+	rep := strykerReport{
+		SchemaVersion: strykerSchemaVersion,
+		Thresholds:    strykerThresholds{High: 80, Low: 60},
+		ProjectRoot:   results.SrcDir,
+		Files:         make(map[string]strykerFile),
+		Framework:     strykerFramework{Name: "gremlins"},
+	}
+	ids := make(map[string]int)
+	for _, m := range results.Mutants {
+		name := filepath.ToSlash(m.Position().Filename)
+		f, ok := rep.Files[name]
+		if !ok {
+			f = strykerFile{Language: "go", Mutants: []strykerMutant{}}
+			if src, err := os.ReadFile(filepath.Join(results.SrcDir, m.Position().Filename)); err == nil {
+				f.Source = string(src)
+			}
+		}
+		sm := newStrykerMutant(m)
+		sm.ID = strykerID(name, sm, ids)
+		f.Mutants = append(f.Mutants, sm)
+		rep.Files[name] = f
+	}
+
+	data, err := json.Marshal(rep)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, data, 0600)
+}
+
+// strykerID derives the id of the mutant from its file, location, type and
+// replacement, so that the same mutant has the same id in every run. The
+// identical mutants get a counter suffix, since the ids must be unique.
+func strykerID(file string, m strykerMutant, ids map[string]int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%d:%s:%s", file, m.Location.Start.Line, m.Location.Start.Column, m.MutatorName, m.Replacement)))
+	id := hex.EncodeToString(sum[:8])
+	ids[id]++
+	if n := ids[id]; n > 1 {
+		id += "-" + strconv.Itoa(n)
+	}
+
+	return id
+}
+
+func newStrykerMutant(m mutator.Mutator) strykerMutant {
+	start := m.Position()
+	end := start
+	end.Column++
+	var replacement string
+	if d, ok := m.(mutator.Describer); ok {
+		replacement = d.Replacement()
+		start, end = d.Location()
+	}
+
+	return strykerMutant{
+		MutatorName: m.Type().String(),
+		Replacement: replacement,
+		Location: strykerLocation{
+			Start: strykerPosition{Line: start.Line, Column: start.Column},
+			End:   strykerPosition{Line: end.Line, Column: end.Column},
+		},
+		Status: strykerStatus(m.Status()),
+	}
+}
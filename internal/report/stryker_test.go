@@ -0,0 +1,211 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package report_test
+
+import (
+	"encoding/json"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+
+	"github.com/singhnishant94/gremlins/internal/configuration"
+	"github.com/singhnishant94/gremlins/internal/mutator"
+	"github.com/singhnishant94/gremlins/internal/report"
+)
+
+type describedMutant struct {
+	stubMutant
+	replacement string
+	start, end  [2]int
+}
+
+func (d describedMutant) Replacement() string {
+	return d.replacement
+}
+
+func (d describedMutant) Location() (start, end token.Position) {
+	start = d.Position()
+	start.Line, start.Column = d.start[0], d.start[1]
+	end = d.Position()
+	end.Line, end.Column = d.end[0], d.end[1]
+
+	return start, end
+}
+
+func TestReportToStryker(t *testing.T) {
+	srcDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(srcDir, "file1.go"), []byte(htmlSource), 0600); err != nil {
+		t.Fatal(err)
+	}
+	data := report.Results{
+		Module: "example.com",
+		SrcDir: srcDir,
+		Mutants: []mutator.Mutator{
+			describedMutant{
+				stubMutant:  stubMutant{status: mutator.Lived, mutantType: mutator.ConditionalsBoundary, position: newPosition("file1.go", 11, 4)},
+				replacement: ">=",
+				start:       [2]int{4, 11},
+				end:         [2]int{4, 12},
+			},
+			stubMutant{status: mutator.Killed, mutantType: mutator.ConditionalsNegation, position: newPosition("file1.go", 11, 4)},
+			stubMutant{status: mutator.NotCovered, mutantType: mutator.ArithmeticBase, position: newPosition("missing.go", 3, 2)},
+			stubMutant{status: mutator.NotViable, mutantType: mutator.ArithmeticBase, position: newPosition("missing.go", 3, 2)},
+			stubMutant{status: mutator.TimedOut, mutantType: mutator.ArithmeticBase, position: newPosition("missing.go", 3, 2)},
+			stubMutant{status: mutator.Skipped, mutantType: mutator.ArithmeticBase, position: newPosition("missing.go", 3, 2)},
+		},
+		Elapsed: 2 * time.Second,
+	}
+	output := filepath.Join(t.TempDir(), "mutation.json")
+	viper.Set(configuration.UnleashOutputStrykerKey, output)
+	defer viper.Reset()
+
+	if err := report.Do(data); err != nil {
+		t.Fatal("error not expected")
+	}
+
+	file, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(file, &got); err != nil {
+		t.Fatal(err)
+	}
+	// The ids are checked by TestReportToStrykerIDs.
+	for _, f := range got["files"].(map[string]any) {
+		for _, m := range f.(map[string]any)["mutants"].([]any) {
+			m.(map[string]any)["id"] = ""
+		}
+	}
+	loc := func(sl, sc, el, ec float64) map[string]any {
+		return map[string]any{
+			"start": map[string]any{"line": sl, "column": sc},
+			"end":   map[string]any{"line": el, "column": ec},
+		}
+	}
+	mutant := func(name, replacement, status string, location map[string]any) any {
+		return map[string]any{"id": "", "mutatorName": name, "replacement": replacement, "status": status, "location": location}
+	}
+	want := map[string]any{
+		"schemaVersion": "2",
+		"thresholds":    map[string]any{"high": float64(80), "low": float64(60)},
+		"projectRoot":   srcDir,
+		"framework":     map[string]any{"name": "gremlins"},
+		"files": map[string]any{
+			"file1.go": map[string]any{
+				"language": "go",
+				"source":   htmlSource,
+				"mutants": []any{
+					mutant("CONDITIONALS_BOUNDARY", ">=", "Survived", loc(4, 11, 4, 12)),
+					mutant("CONDITIONALS_NEGATION", "", "Killed", loc(4, 11, 4, 12)),
+				},
+			},
+			"missing.go": map[string]any{
+				"language": "go",
+				"source":   "",
+				"mutants": []any{
+					mutant("ARITHMETIC_BASE", "", "NoCoverage", loc(2, 3, 2, 4)),
+					mutant("ARITHMETIC_BASE", "", "CompileError", loc(2, 3, 2, 4)),
+					mutant("ARITHMETIC_BASE", "", "Timeout", loc(2, 3, 2, 4)),
+					mutant("ARITHMETIC_BASE", "", "Ignored", loc(2, 3, 2, 4)),
+				},
+			},
+		},
+	}
+	if !cmp.Equal(got, want) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestReportToStrykerIDs(t *testing.T) {
+	mutants := []mutator.Mutator{
+		describedMutant{
+			stubMutant:  stubMutant{status: mutator.Lived, mutantType: mutator.ConditionalsBoundary, position: newPosition("file1.go", 11, 4)},
+			replacement: ">=",
+			start:       [2]int{4, 11},
+			end:         [2]int{4, 12},
+		},
+		describedMutant{
+			stubMutant:  stubMutant{status: mutator.Killed, mutantType: mutator.ConditionalsBoundary, position: newPosition("file1.go", 11, 4)},
+			replacement: "<=",
+			start:       [2]int{4, 11},
+			end:         [2]int{4, 12},
+		},
+		stubMutant{status: mutator.NotCovered, mutantType: mutator.ConditionalsNegation, position: newPosition("file1.go", 11, 4)},
+		stubMutant{status: mutator.TimedOut, mutantType: mutator.ConditionalsNegation, position: newPosition("file1.go", 11, 4)},
+		stubMutant{status: mutator.NotViable, mutantType: mutator.ConditionalsNegation, position: newPosition("file2.go", 11, 4)},
+	}
+	reversed := make([]mutator.Mutator, len(mutants))
+	for i, m := range mutants {
+		reversed[len(mutants)-1-i] = m
+	}
+
+	// ids returns the ids of the mutants by status, which tells them apart.
+	ids := func(mutants []mutator.Mutator) map[string]string {
+		output := filepath.Join(t.TempDir(), "mutation.json")
+		viper.Set(configuration.UnleashOutputStrykerKey, output)
+		defer viper.Reset()
+		if err := report.Do(report.Results{Module: "example.com", SrcDir: t.TempDir(), Mutants: mutants}); err != nil {
+			t.Fatal("error not expected")
+		}
+		file, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got struct {
+			Files map[string]struct {
+				Mutants []struct {
+					ID     string `json:"id"`
+					Status string `json:"status"`
+				} `json:"mutants"`
+			} `json:"files"`
+		}
+		if err := json.Unmarshal(file, &got); err != nil {
+			t.Fatal(err)
+		}
+		res := make(map[string]string)
+		for _, f := range got.Files {
+			for _, m := range f.Mutants {
+				res[m.Status] = m.ID
+			}
+		}
+
+		return res
+	}
+
+	got := ids(mutants)
+	unique := make(map[string]bool)
+	for _, id := range got {
+		unique[id] = true
+	}
+	if len(unique) != len(mutants) {
+		t.Errorf("expected %d unique ids, got %v", len(mutants), got)
+	}
+	// The identical NOT COVERED and TIMED OUT mutants are told apart by the
+	// order, the others keep their id.
+	gotReversed := ids(reversed)
+	for _, status := range []string{"Survived", "Killed", "CompileError"} {
+		if got[status] != gotReversed[status] {
+			t.Errorf("expected the %s mutant to keep its id, got %q and %q", status, got[status], gotReversed[status])
+		}
+	}
+}
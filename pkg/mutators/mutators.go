@@ -0,0 +1,70 @@
+/*
+ * Copyright 2022 The Gremlins Authors
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+// Package mutators allows to define project-specific mutators.
+//
+// A Mutator is registered with Register, usually in an init function of
+// a custom build of Gremlins, whose main calls Execute. Once registered,
+// the Mutator gets a configuration key and a flag as the builtin mutators,
+// and its mutants are shown in the reports under its name:
+//
+//	func init() {
+//		mutators.Register(FixedTime{}, false)
+//	}
+package mutators
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+
+	"github.com/singhnishant94/gremlins/cmd"
+	"github.com/singhnishant94/gremlins/internal/engine"
+	"github.com/singhnishant94/gremlins/internal/log"
+)
+
+// Mutator finds the mutations of a project-specific mutant type.
+//
+// Name is the name of the mutant type, in upper snake case, such as
+// FIXED_TIME. Mutations is called for each node of the syntax tree of the
+// files, and returns the mutations of the node. Its info argument is nil if
+// the type information of the package is not available.
+type Mutator = engine.CustomMutator
+
+// Mutation is a mutation found by a Mutator.
+//
+// Node is the mutated node and Replacement is the code put in place of it,
+// as shown in the reports. Apply changes the syntax tree and returns the
+// function which puts it back as it was. The syntax tree is shared among
+// the mutants, so Apply must not change anything else.
+type Mutation = engine.CustomMutation
+
+// Register registers the Mutator, enabled by default or not. It panics if
+// the name of the Mutator is not in upper snake case, or if it is already
+// used.
+func Register(m Mutator, enabled bool) {
+	engine.RegisterCustom(m, enabled)
+}
+
+// Execute runs Gremlins with the registered Mutator, as the gremlins
+// command does. The error it returns implements ExitCode() int when
+// Gremlins has to exit with a specific code, such as when a threshold is
+// not met.
+func Execute(ctx context.Context, version string) error {
+	log.Init(color.Output, color.Error)
+
+	return cmd.Execute(ctx, version)
+}